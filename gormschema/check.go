@@ -0,0 +1,98 @@
+package gormschema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sdk/recordriver"
+	"gorm.io/gorm"
+	gschema "gorm.io/gorm/schema"
+)
+
+// Check defines a table-level CHECK constraint. Unlike the check tag of GORM,
+// its expression may reference multiple columns of the table.
+type Check struct {
+	name string
+	expr string
+}
+
+// NewCheck returns a CHECK constraint with the given name and expression. For example:
+//
+//	gormschema.NewCheck("chk_price_discount", "discount_price < price")
+func NewCheck(name, expr string) Check {
+	return Check{name: name, expr: expr}
+}
+
+// modelChecks returns the checks defined by the Checks method of the given model, if any,
+// and the checks of its enum fields.
+func (m *migrator) modelChecks(model any) ([]Check, error) {
+	var checks []Check
+	if md, ok := model.(interface {
+		Checks(string) []Check
+	}); ok {
+		checks = md.Checks(m.Dialector.Name())
+	}
+	enums, err := m.enumChecks(model)
+	if err != nil {
+		return nil, err
+	}
+	return append(checks, enums...), nil
+}
+
+// CreateChecks creates the CHECK constraints defined by the Checks method of the given models.
+func (m *migrator) CreateChecks(models []any) error {
+	for _, model := range models {
+		checks, err := m.modelChecks(model)
+		if err != nil {
+			return err
+		}
+		if len(checks) == 0 {
+			continue
+		}
+		err = m.RunWithValue(model, func(stmt *gorm.Statement) error {
+			for _, c := range checks {
+				if c.name == "" || c.expr == "" {
+					return fmt.Errorf("check constraint on %s: missing name or expression", stmt.Schema.Table)
+				}
+				chk := &gschema.CheckConstraint{Name: c.name, Constraint: c.expr}
+				sql, vars := chk.Build()
+				// SQLite does not support adding constraints to existing tables.
+				if m.Dialector.Name() == "sqlite" {
+					if err := m.inlineConstraint(stmt, sql, vars); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := m.DB.Exec("ALTER TABLE ? ADD "+sql, append([]any{m.CurrentTable(stmt)}, vars...)...).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inlineConstraint appends the given constraint definition to the
+// CREATE TABLE statement recorded for the table of the statement.
+func (m *migrator) inlineConstraint(stmt *gorm.Statement, sql string, vars []any) error {
+	s, ok := recordriver.Session(m.session)
+	if !ok {
+		return errors.New("gorm db session not found")
+	}
+	var (
+		prefix = m.DB.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Exec("CREATE TABLE ? (", m.CurrentTable(stmt)) })
+		def    = m.DB.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Exec(sql, vars...) })
+	)
+	for i, st := range s.Statements {
+		if j := strings.LastIndex(st, ")"); strings.HasPrefix(st, prefix) && j > 0 {
+			s.Statements[i] = st[:j] + "," + def + st[j:]
+			return nil
+		}
+	}
+	return fmt.Errorf("missing CREATE TABLE statement for %s", stmt.Schema.Table)
+}
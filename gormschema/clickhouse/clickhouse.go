@@ -0,0 +1,27 @@
+// Package clickhouse registers the clickhouse dialect of gormschema. It is kept in a separate
+// package to avoid linking the ClickHouse driver into programs that do not use it:
+//
+//	import _ "ariga.io/atlas-provider-gorm/gormschema/clickhouse"
+//
+// The table engine of a model is set by its Engine method. See gormschema.Engine for details.
+package clickhouse
+
+import (
+	"cmp"
+	"database/sql/driver"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func init() {
+	gormschema.RegisterDialect("clickhouse", func(c *gormschema.DialectConn) (gorm.Dialector, error) {
+		db, err := c.Open()
+		if err != nil {
+			return nil, err
+		}
+		c.SetResponse("SELECT version()", []string{"version()"}, []driver.Value{cmp.Or(c.Version, "24.8")})
+		return clickhouse.New(clickhouse.Config{Conn: db}), nil
+	})
+}
@@ -0,0 +1,323 @@
+package gormschema
+
+import (
+	"cmp"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"ariga.io/atlas/sdk/recordriver"
+	spannergorm "github.com/googleapis/go-gorm-spanner"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+	gormig "gorm.io/gorm/migrator"
+	gschema "gorm.io/gorm/schema"
+)
+
+type (
+	// DialectFactory builds the gorm.Dialector of a dialect on top of the recording connection.
+	DialectFactory func(conn *DialectConn) (gorm.Dialector, error)
+	// DialectConn describes the recording connection a dialect is built on. The statements executed
+	// on the connection are recorded as the schema, and its queries return the responses set using
+	// SetResponse, or no rows.
+	DialectConn struct {
+		// Name is the name the dialect is registered with.
+		Name string
+		// DriverName and DSN open the connection using database/sql.
+		DriverName string
+		DSN        string
+		// Version is the version set by the WithDialectVersion option, if any.
+		Version string
+	}
+)
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = make(map[string]DialectFactory)
+)
+
+func init() {
+	RegisterDialect("sqlite", func(c *DialectConn) (gorm.Dialector, error) {
+		db, err := c.Open()
+		if err != nil {
+			return nil, err
+		}
+		c.SetResponse("select sqlite_version()", []string{"sqlite_version()"}, []driver.Value{cmp.Or(c.Version, "3.30.1")})
+		return sqlite.Dialector{Conn: db}, nil
+	})
+	for _, name := range []string{"mysql", "mariadb"} {
+		RegisterDialect(name, func(c *DialectConn) (gorm.Dialector, error) {
+			c.SetResponse("SELECT VERSION()", []string{"VERSION()"}, []driver.Value{serverVersion(c.Version, name == "mariadb")})
+			return mysql.New(mysql.Config{DriverName: c.DriverName, DSN: c.DSN}), nil
+		})
+	}
+	RegisterDialect("postgres", func(c *DialectConn) (gorm.Dialector, error) {
+		if err := c.noVersion(); err != nil {
+			return nil, err
+		}
+		return postgres.New(postgres.Config{DriverName: c.DriverName, DSN: c.DSN}), nil
+	})
+	RegisterDialect("cockroach", func(c *DialectConn) (gorm.Dialector, error) {
+		v, err := parseVersion(cmp.Or(c.Version, "25.2"))
+		if err != nil {
+			return nil, fmt.Errorf("cockroach: %w", err)
+		}
+		return newPostgresVariant(c, v, cockroachDataType, cockroachUnsupported), nil
+	})
+	RegisterDialect("yugabyte", func(c *DialectConn) (gorm.Dialector, error) {
+		if err := c.noVersion(); err != nil {
+			return nil, err
+		}
+		return newPostgresVariant(c, nil, nil, yugabyteUnsupported), nil
+	})
+	RegisterDialect("sqlserver", func(c *DialectConn) (gorm.Dialector, error) {
+		if err := c.noVersion(); err != nil {
+			return nil, err
+		}
+		return sqlserver.New(sqlserver.Config{DriverName: c.DriverName, DSN: c.DSN}), nil
+	})
+	RegisterDialect("spanner", func(c *DialectConn) (gorm.Dialector, error) {
+		if err := c.noVersion(); err != nil {
+			return nil, err
+		}
+		return spannergorm.New(spannergorm.Config{
+			DriverName:                 c.DriverName,
+			DisableAutoMigrateBatching: true,
+			DSN:                        c.DSN,
+		}), nil
+	})
+}
+
+// RegisterDialect makes a dialect available by the provided name, which can be passed to New or to the
+// --dialect flag of the CLI. For example, a dialect for a third-party GORM driver can be registered in
+// the init function of the models package:
+//
+//	func init() {
+//		gormschema.RegisterDialect("duckdb", func(c *gormschema.DialectConn) (gorm.Dialector, error) {
+//			db, err := c.Open()
+//			if err != nil {
+//				return nil, err
+//			}
+//			return duckdb.New(duckdb.Config{Conn: db}), nil
+//		})
+//	}
+//
+// If RegisterDialect is called twice with the same name or if factory is nil, it panics.
+func RegisterDialect(name string, factory DialectFactory) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	if factory == nil {
+		panic("gormschema: RegisterDialect factory is nil")
+	}
+	if _, dup := dialects[name]; dup {
+		panic("gormschema: RegisterDialect called twice for dialect " + name)
+	}
+	dialects[name] = factory
+}
+
+// Dialects returns a sorted list of the names of the registered dialects.
+func Dialects() []string {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	return slices.Sorted(maps.Keys(dialects))
+}
+
+// Open opens the recording connection.
+func (c *DialectConn) Open() (*sql.DB, error) {
+	return sql.Open(c.DriverName, c.DSN)
+}
+
+// SetResponse sets the rows returned by the given query, such as the version probe
+// executed by the dialector when it is initialized.
+func (c *DialectConn) SetResponse(query string, cols []string, rows ...[]driver.Value) {
+	recordriver.SetResponse(c.DSN, query, &recordriver.Response{Cols: cols, Data: rows})
+}
+
+// noVersion returns an error if a version was set for a dialect that does not support it.
+func (c *DialectConn) noVersion() error {
+	if c.Version != "" {
+		return fmt.Errorf("dialect version is not supported for %s", c.Name)
+	}
+	return nil
+}
+
+// dialectPackages maps the dialects registered by separate packages to their import paths.
+var dialectPackages = map[string]string{
+	"clickhouse": "ariga.io/atlas-provider-gorm/gormschema/clickhouse",
+}
+
+// openDialector returns the gorm.Dialector of the given dialect, built on top of the recording session.
+func openDialector(name, version, session string) (gorm.Dialector, error) {
+	dialectsMu.RLock()
+	factory, ok := dialects[name]
+	dialectsMu.RUnlock()
+	if !ok {
+		if pkg, ok := dialectPackages[name]; ok {
+			return nil, fmt.Errorf("unsupported engine: %s (missing import _ %q)", name, pkg)
+		}
+		return nil, fmt.Errorf("unsupported engine: %s", name)
+	}
+	return factory(&DialectConn{Name: name, DriverName: "recordriver", DSN: session, Version: version})
+}
+
+// serverVersion returns the version reported by the MySQL or MariaDB server. The GORM
+// dialector expects a full version (e.g. "5.7.0") and detects MariaDB by its suffix.
+func serverVersion(v string, mariadb bool) string {
+	switch {
+	case v == "" && mariadb:
+		v = "10.11.0"
+	case v == "":
+		v = "8.0.24"
+	}
+	for strings.Count(v, ".") < 2 && !strings.Contains(v, "-") {
+		v += ".0"
+	}
+	if mariadb && !strings.Contains(v, "MariaDB") {
+		v += "-MariaDB"
+	}
+	return v
+}
+
+// formatVersion formats the numeric components of a version, e.g. "24.3".
+func formatVersion(v []int) string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseVersion parses a version such as "23.2" or "24.3.1" into its numeric components.
+func parseVersion(v string) ([]int, error) {
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", v)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+type (
+	// postgresVariant is a PostgreSQL-compatible database served by the GORM PostgreSQL driver.
+	// Models receive "postgres" as the dialect name, while the column types and the statements
+	// supported by the database are adjusted to it.
+	postgresVariant struct {
+		*postgres.Dialector
+		name string
+		// version is the version of the database the DDL is generated for, if it is known.
+		version []int
+		// dataType returns the type of the fields whose type differs from PostgreSQL, or "" for the rest.
+		dataType    func(*gschema.Field) string
+		unsupported []unsupportedStmt
+	}
+	// unsupportedStmt describes the statements that create objects not supported by a database.
+	unsupportedStmt struct {
+		re   *regexp.Regexp
+		what string
+		// since is the version the objects are supported from, or nil if they are not supported at all.
+		since []int
+	}
+)
+
+var (
+	// cockroachUnsupported lists the objects CockroachDB does not support, or supports only from a given version.
+	cockroachUnsupported = []unsupportedStmt{
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?CONSTRAINT\s+TRIGGER\b`), what: "constraint triggers"},
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?TRIGGER\b`), what: "triggers", since: []int{24, 3}},
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?FUNCTION\b.*\bRETURNS\s+TRIGGER\b`), what: "triggers", since: []int{24, 3}},
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?PROCEDURE\b`), what: "procedures", since: []int{23, 2}},
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?FUNCTION\b.*\bLANGUAGE\s+'?plpgsql\b`), what: "PL/pgSQL functions", since: []int{23, 2}},
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?FUNCTION\b`), what: "functions", since: []int{22, 2}},
+	}
+	// yugabyteUnsupported lists the objects YugabyteDB does not support.
+	yugabyteUnsupported = []unsupportedStmt{
+		{re: regexp.MustCompile(`(?is)^\s*CREATE\s+(?:UNIQUE\s+)?INDEX\b.*\bUSING\s+(?:GIST|BRIN|SPGIST)\b`), what: "GiST, BRIN and SP-GiST indexes"},
+	}
+)
+
+// newPostgresVariant returns the dialector of a PostgreSQL-compatible database.
+func newPostgresVariant(c *DialectConn, version []int, dataType func(*gschema.Field) string, unsupported []unsupportedStmt) postgresVariant {
+	return postgresVariant{
+		Dialector:   postgres.New(postgres.Config{DriverName: c.DriverName, DSN: c.DSN}).(*postgres.Dialector),
+		name:        c.Name,
+		version:     version,
+		dataType:    dataType,
+		unsupported: unsupported,
+	}
+}
+
+// Initialize initializes the PostgreSQL dialector, and rejects the statements
+// creating objects that are not supported by the database.
+func (d postgresVariant) Initialize(db *gorm.DB) error {
+	if err := d.Dialector.Initialize(db); err != nil {
+		return err
+	}
+	return db.Callback().Raw().Before("gorm:raw").Register("gormschema:"+d.name, func(db *gorm.DB) {
+		stmt := db.Statement.SQL.String()
+		for _, u := range d.unsupported {
+			if !u.re.MatchString(stmt) {
+				continue
+			}
+			line, _, _ := strings.Cut(strings.TrimSpace(stmt), "\n")
+			switch {
+			case u.since == nil:
+				db.AddError(fmt.Errorf("%s are not supported by %s: %s", u.what, d.name, line))
+				return
+			case slices.Compare(d.version, u.since) < 0:
+				db.AddError(fmt.Errorf("%s are not supported by %s before version %s: %s", u.what, d.name, formatVersion(u.since), line))
+				return
+			}
+		}
+	})
+}
+
+// Migrator returns the PostgreSQL migrator, which resolves the column types using the variant.
+func (d postgresVariant) Migrator(db *gorm.DB) gorm.Migrator {
+	return postgres.Migrator{Migrator: gormig.Migrator{Config: gormig.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+// DataTypeOf returns the column type of the given field.
+func (d postgresVariant) DataTypeOf(f *gschema.Field) string {
+	if d.dataType != nil {
+		if t := d.dataType(f); t != "" {
+			return t
+		}
+	}
+	return d.Dialector.DataTypeOf(f)
+}
+
+// cockroachDataType maps the integer fields, as the SERIAL types of CockroachDB generate unique_rowid()
+// values rather than sequential ones, and its INTEGER type is an alias for INT8.
+func cockroachDataType(f *gschema.Field) string {
+	if f.AutoIncrement {
+		return "bigint DEFAULT unique_rowid()"
+	}
+	if f.DataType != gschema.Int && f.DataType != gschema.Uint {
+		return ""
+	}
+	size := f.Size
+	if f.DataType == gschema.Uint {
+		size++
+	}
+	if size > 16 && size <= 32 {
+		return "int4"
+	}
+	return ""
+}
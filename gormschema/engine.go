@@ -0,0 +1,107 @@
+package gormschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// Engine defines the table engine of a ClickHouse table, which cannot be expressed
+	// using GORM struct tags. Models define it using the Engine method, which is used by
+	// the dialect registered by the gormschema/clickhouse package. For example:
+	//
+	//	func (Event) Engine(string) gormschema.Engine {
+	//		return gormschema.NewEngine("MergeTree()",
+	//			gormschema.EngineOrderBy("user_id", "created_at"),
+	//			gormschema.EnginePartitionBy("toYYYYMM(created_at)"),
+	//			gormschema.EngineTTL("created_at + INTERVAL 1 YEAR"),
+	//		)
+	//	}
+	Engine struct {
+		name        string
+		orderBy     []string
+		partitionBy string
+		ttl         string
+	}
+	// EngineOption configures an Engine.
+	EngineOption func(*Engine)
+)
+
+// NewEngine receives the engine name, with its parameters, and a list of EngineOption to build
+// an Engine. For example, "MergeTree()" or "ReplacingMergeTree(version)".
+func NewEngine(name string, opts ...EngineOption) Engine {
+	e := Engine{name: name}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
+
+// EngineOrderBy sets the sorting key of the table. Columns and expressions are both accepted.
+// Tables of the MergeTree family that do not define a sorting key are ordered by tuple().
+func EngineOrderBy(exprs ...string) EngineOption {
+	return func(e *Engine) {
+		e.orderBy = append(e.orderBy, exprs...)
+	}
+}
+
+// EnginePartitionBy sets the partitioning key of the table. For example, "toYYYYMM(created_at)".
+func EnginePartitionBy(expr string) EngineOption {
+	return func(e *Engine) {
+		e.partitionBy = expr
+	}
+}
+
+// EngineTTL sets the table TTL expression. For example, "created_at + INTERVAL 1 YEAR".
+func EngineTTL(expr string) EngineOption {
+	return func(e *Engine) {
+		e.ttl = expr
+	}
+}
+
+// String returns the engine clause of the CREATE TABLE statement.
+func (e Engine) String() string {
+	var b strings.Builder
+	b.WriteString("ENGINE=" + e.name)
+	switch {
+	case len(e.orderBy) == 1:
+		b.WriteString(" ORDER BY " + e.orderBy[0])
+	case len(e.orderBy) > 1:
+		b.WriteString(" ORDER BY (" + strings.Join(e.orderBy, ", ") + ")")
+	case strings.Contains(e.name, "MergeTree"):
+		b.WriteString(" ORDER BY tuple()")
+	}
+	if e.partitionBy != "" {
+		b.WriteString(" PARTITION BY " + e.partitionBy)
+	}
+	if e.ttl != "" {
+		b.WriteString(" TTL " + e.ttl)
+	}
+	return b.String()
+}
+
+// migrateTables creates the tables of the given models. On ClickHouse, each table is
+// created with the engine defined by the Engine method of its model, if any.
+func (m *migrator) migrateTables(db *gorm.DB, models []any) error {
+	if m.Dialector.Name() != "clickhouse" {
+		return db.AutoMigrate(models...)
+	}
+	for _, model := range models {
+		tx := db
+		if md, ok := model.(interface {
+			Engine(string) Engine
+		}); ok {
+			e := md.Engine(m.Dialector.Name())
+			if e.name == "" {
+				return fmt.Errorf("engine of %T: missing name", model)
+			}
+			tx = db.Set("gorm:table_options", e.String())
+		}
+		if err := tx.AutoMigrate(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package gormschema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gschema "gorm.io/gorm/schema"
+)
+
+type (
+	// EnumDefiner is implemented by Go named types whose values are restricted to a fixed set,
+	// such as a string type with a list of constants. Fields of string-based types are mapped to
+	// an ENUM type on PostgreSQL and MySQL, and to a CHECK constraint on other dialects. Fields
+	// of integer-based types are always mapped to a CHECK constraint.
+	//
+	// The name of a PostgreSQL enum type is the snake-cased name of the Go type, unless
+	// the type implements the EnumName method.
+	EnumDefiner interface {
+		EnumValues() []string
+	}
+	// enumType describes the database enum of an EnumDefiner.
+	enumType struct {
+		name    string
+		values  []string
+		integer bool
+	}
+)
+
+// fieldEnum returns the enum of the given field, if its type implements EnumDefiner.
+func fieldEnum(db *gorm.DB, f *gschema.Field) (*enumType, bool) {
+	if f.IndirectFieldType == nil {
+		return nil, false
+	}
+	v, ok := reflect.New(f.IndirectFieldType).Interface().(EnumDefiner)
+	if !ok {
+		return nil, false
+	}
+	e := &enumType{values: v.EnumValues(), integer: f.GORMDataType == gschema.Int || f.GORMDataType == gschema.Uint}
+	if n, ok := v.(interface{ EnumName() string }); ok {
+		e.name = n.EnumName()
+	} else {
+		e.name = db.NamingStrategy.ColumnName("", f.IndirectFieldType.Name())
+	}
+	return e, true
+}
+
+// check reports if the enum is defined using a CHECK constraint on the given dialect.
+func (e *enumType) check(dialect string) bool {
+	return e.integer || !slices.Contains([]string{"postgres", "mysql"}, dialect)
+}
+
+// literals returns the values of the enum as a list of SQL literals.
+func (e *enumType) literals() (string, error) {
+	if len(e.values) == 0 {
+		return "", fmt.Errorf("enum %s: missing values", e.name)
+	}
+	lits := make([]string, len(e.values))
+	for i, v := range e.values {
+		if !e.integer {
+			lits[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+			continue
+		}
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return "", fmt.Errorf("enum %s: invalid integer value %q", e.name, v)
+		}
+		lits[i] = v
+	}
+	return strings.Join(lits, ", "), nil
+}
+
+// DefineEnums sets the column types of the fields of the given models whose types implement
+// EnumDefiner, unless they are defined using CHECK constraints. GORM caches the parsed models
+// per database, therefore, the fields are updated for both the database the tables are migrated
+// with and the database of the migrator. It returns the enum types that should be created before
+// the tables.
+func (m *migrator) DefineEnums(models []any) ([]*enumType, error) {
+	var (
+		enums   []*enumType
+		dialect = m.Dialector.Name()
+	)
+	for _, d := range []*gorm.DB{m.db, m.DB} {
+		for _, model := range models {
+			stmt := &gorm.Statement{DB: d}
+			if err := stmt.Parse(model); err != nil {
+				return nil, err
+			}
+			for _, f := range stmt.Schema.Fields {
+				e, ok := fieldEnum(d, f)
+				if !ok {
+					continue
+				}
+				lits, err := e.literals()
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case e.check(dialect):
+					continue
+				case dialect == "postgres":
+					f.DataType = gschema.DataType(d.Statement.Quote(e.name))
+				default:
+					f.DataType = gschema.DataType("ENUM(" + lits + ")")
+					continue
+				}
+				if d != m.DB {
+					continue
+				}
+				i := slices.IndexFunc(enums, func(x *enumType) bool { return x.name == e.name })
+				switch {
+				case i == -1:
+					enums = append(enums, e)
+				case !slices.Equal(enums[i].values, e.values):
+					return nil, fmt.Errorf("enum %s: conflicting values", e.name)
+				}
+			}
+		}
+	}
+	return enums, nil
+}
+
+// CreateEnums creates the given enum types.
+func (m *migrator) CreateEnums(enums []*enumType) error {
+	for _, e := range enums {
+		lits, err := e.literals()
+		if err != nil {
+			return err
+		}
+		if err := m.DB.Exec("CREATE TYPE ? AS ENUM ("+lits+")", clause.Table{Name: e.name}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enumChecks returns the CHECK constraints of the enum fields of the given model.
+func (m *migrator) enumChecks(model any) ([]Check, error) {
+	stmt := &gorm.Statement{DB: m.DB}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	var checks []Check
+	for _, f := range stmt.Schema.Fields {
+		if f.DBName == "" || f.IgnoreMigration {
+			continue
+		}
+		e, ok := fieldEnum(m.DB, f)
+		if !ok || !e.check(m.Dialector.Name()) {
+			continue
+		}
+		// Invalid values are reported by DefineEnums.
+		if lits, err := e.literals(); err == nil {
+			name := m.DB.NamingStrategy.CheckerName(stmt.Schema.Table, f.DBName)
+			checks = append(checks, NewCheck(name, fmt.Sprintf("%s IN (%s)", m.DB.Statement.Quote(f.DBName), lits)))
+		}
+	}
+	return checks, nil
+}
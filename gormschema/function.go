@@ -0,0 +1,60 @@
+package gormschema
+
+import (
+	"reflect"
+	"regexp"
+)
+
+type (
+	// FunctionOption implemented by FUNCTION's and PROCEDURE's related options
+	FunctionOption interface {
+		isFunctionOption()
+		apply(*schemaBuilder)
+	}
+	// FunctionDefiner defines a stored function.
+	FunctionDefiner interface {
+		FunctionDef(dialect string) []FunctionOption
+	}
+	// ProcedureDefiner defines a stored procedure.
+	ProcedureDefiner interface {
+		ProcedureDef(dialect string) []FunctionOption
+	}
+)
+
+// routineNameRe extracts the name of a function or procedure from its CREATE statement.
+var routineNameRe = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:FUNCTION|PROCEDURE|PROC)\s+([^\s(]+)`)
+
+// routineType returns the type of the given function-based or procedure-based model.
+func routineType(model any) string {
+	if _, ok := model.(ProcedureDefiner); ok {
+		return "procedure"
+	}
+	return "function"
+}
+
+// buildRoutine applies the options of the given function-based or procedure-based model.
+func (m *migrator) buildRoutine(r any) *schemaBuilder {
+	var (
+		b    = &schemaBuilder{db: m.DB}
+		opts []FunctionOption
+	)
+	switch r := r.(type) {
+	case FunctionDefiner:
+		opts = r.FunctionDef(m.Dialector.Name())
+	case ProcedureDefiner:
+		opts = r.ProcedureDef(m.Dialector.Name())
+	}
+	for _, o := range opts {
+		o.apply(b)
+	}
+	return b
+}
+
+// routineName returns the name of the given function-based or procedure-based model, as
+// defined by its CREATE statement. If it cannot be extracted, the snake-cased type name is used.
+func (m *migrator) routineName(r any) string {
+	if matches := routineNameRe.FindStringSubmatch(m.buildRoutine(r).createStmt); matches != nil {
+		return unquoter.Replace(matches[1])
+	}
+	return m.DB.NamingStrategy.ColumnName("", indirect(reflect.TypeOf(r)).Name())
+}
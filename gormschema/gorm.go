@@ -2,23 +2,21 @@ package gormschema
 
 import (
 	"database/sql"
-	"database/sql/driver"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
+	"sync/atomic"
 
 	"ariga.io/atlas/sdk/recordriver"
-	spannergorm "github.com/googleapis/go-gorm-spanner"
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/postgres"
-	"gorm.io/driver/sqlite"
-	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	gormig "gorm.io/gorm/migrator"
+	gschema "gorm.io/gorm/schema"
 )
 
 type (
@@ -29,9 +27,15 @@ type (
 		config            *gorm.Config
 		beforeAutoMigrate []func(*gorm.DB) error
 		modelPos          map[any]string
+		output            Output
+		hclSchema         string
+		version           string
+		schemas           map[reflect.Type]string
 	}
 	// Option configures the Loader.
 	Option func(*Loader)
+	// Output defines the format of the schema returned by the Loader.
+	Output string
 	// ViewOption implemented by VIEW's related options
 	ViewOption interface {
 		isViewOption()
@@ -55,9 +59,25 @@ type (
 	schemaBuilder struct {
 		db         *gorm.DB
 		createStmt string
+		// viewDef holds the query of a view created by the BuildStmt option.
+		viewDef string
 		// viewName is only used for the BuildStmt option.
 		// BuildStmt returns only a subquery; viewName helps to create a full CREATE VIEW statement.
 		viewName string
+		// materialized and noData are set by the Materialized and WithNoData options.
+		materialized bool
+		noData       bool
+		// tableName is the table of the model a trigger is defined on, and onView
+		// reports if the model is a view.
+		tableName string
+		onView    bool
+		trigger   triggerDef
+		// deps holds the models declared by the DependsOn option.
+		deps []any
+	}
+	// resource identifies a schema resource by its name and type, e.g. "users" and "table".
+	resource struct {
+		name, typ string
 	}
 )
 
@@ -95,9 +115,60 @@ func WithModelPosition(pos map[any]string) Option {
 	}
 }
 
+// WithDialectVersion sets the version of the database server reported to the GORM dialector,
+// which adjusts the generated DDL accordingly. For example, "5.7" for MySQL or "10.6" for MariaDB.
+// It is supported by the MySQL, MariaDB, SQLite, CockroachDB and ClickHouse dialects, and passed to
+// registered dialects in DialectConn.Version.
+func WithDialectVersion(version string) Option {
+	return func(l *Loader) {
+		l.version = version
+	}
+}
+
+// WithSchema assigns the given models to the named database schema. The tables and views of
+// these models are qualified with the schema name, which is created if it does not exist.
+// Schemas are supported by the PostgreSQL dialect.
+func WithSchema(name string, models ...any) Option {
+	return func(l *Loader) {
+		if l.schemas == nil {
+			l.schemas = make(map[reflect.Type]string)
+		}
+		for _, m := range models {
+			l.schemas[indirect(reflect.TypeOf(m))] = name
+		}
+	}
+}
+
+// Output formats supported by the Loader.
+const (
+	// OutputSQL returns the schema as SQL DDL statements. This is the default output.
+	OutputSQL Output = "sql"
+	// OutputHCL returns the schema as an Atlas HCL document, which does not require
+	// a dev database to be interpreted. It is supported by MySQL, PostgreSQL and SQLite.
+	OutputHCL Output = "hcl"
+	// OutputJSON returns the schema resolved by GORM as a JSON-encoded SchemaInfo.
+	OutputJSON Output = "json"
+)
+
+// WithOutput sets the output format of the Loader.
+func WithOutput(o Output) Option {
+	return func(l *Loader) {
+		l.output = o
+	}
+}
+
+// WithHCLSchema sets the name of the schema that holds the tables and views in the HCL output.
+// It defaults to "public" on PostgreSQL and "main" on SQLite. On MySQL and MariaDB, where
+// the schema is the database, it is required.
+func WithHCLSchema(name string) Option {
+	return func(l *Loader) {
+		l.hclSchema = name
+	}
+}
+
 // New returns a new Loader.
 func New(dialect string, opts ...Option) *Loader {
-	l := &Loader{dialect: dialect, delimiter: ";", config: &gorm.Config{}}
+	l := &Loader{dialect: dialect, delimiter: ";", config: &gorm.Config{}, output: OutputSQL}
 	for _, opt := range opts {
 		opt(l)
 	}
@@ -113,13 +184,15 @@ func (s schemaOption) apply(b *schemaBuilder) {
 	s(b)
 }
 
-func (schemaOption) isViewOption()    {}
-func (schemaOption) isTriggerOption() {}
+func (schemaOption) isViewOption()     {}
+func (schemaOption) isTriggerOption()  {}
+func (schemaOption) isFunctionOption() {}
 
-// CreateStmt accepts raw SQL to create a view or trigger
+// CreateStmt accepts raw SQL to create a view, trigger, function or procedure
 func CreateStmt(stmt string) interface {
 	ViewOption
 	TriggerOption
+	FunctionOption
 } {
 	return schemaOption(func(b *schemaBuilder) {
 		b.createStmt = stmt
@@ -127,115 +200,115 @@ func CreateStmt(stmt string) interface {
 }
 
 // BuildStmt accepts a function with gorm query builder to create a CREATE VIEW statement.
-// With this option, the view's name will be the same as the model's table name
+// With this option, the view's name will be the same as the model's table name.
+// On Spanner, views are created with SQL SECURITY INVOKER, as required by the database.
 func BuildStmt(fn func(db *gorm.DB) *gorm.DB) ViewOption {
 	return schemaOption(func(b *schemaBuilder) {
-		vd := b.db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		b.viewDef = b.db.ToSQL(func(tx *gorm.DB) *gorm.DB {
 			return fn(tx).
 				Unscoped(). // Skip gorm deleted_at filtering.
 				Find(nil)   // Execute the query and convert it to SQL.
 		})
-		b.createStmt = fmt.Sprintf("CREATE VIEW %s AS %s", b.viewName, vd)
+		// The statement is built after all options are applied.
+		b.createStmt = ""
+	})
+}
+
+// Materialized creates the view as a materialized view. Like tables, materialized views
+// may define indexes using the Indexes method. It is supported by the PostgreSQL dialect.
+func Materialized() ViewOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.materialized = true
+	})
+}
+
+// WithNoData creates a materialized view defined by BuildStmt without populating it.
+// By default, materialized views are populated on creation.
+func WithNoData() ViewOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.noData = true
+	})
+}
+
+// DependsOn declares that the view, trigger, function or procedure depends on the given models, which
+// must be loaded along with it. It is created after its dependencies, which replace the ones inferred
+// from its statement, and are also reported using the `-- atlas:deps` directive.
+func DependsOn(models ...any) interface {
+	ViewOption
+	TriggerOption
+	FunctionOption
+} {
+	return schemaOption(func(b *schemaBuilder) {
+		b.deps = append(b.deps, models...)
 	})
 }
 
 // Load loads the models and returns the DDL statements representing the schema.
 func (l *Loader) Load(models ...any) (string, error) {
-	var (
-		views  []ViewDefiner
-		tables []any
-	)
-	for _, obj := range models {
-		switch view := obj.(type) {
-		case ViewDefiner:
-			views = append(views, view)
-		default:
-			tables = append(tables, obj)
-		}
-	}
-	var di gorm.Dialector
-	switch l.dialect {
-	case "sqlite":
-		rd, err := sql.Open("recordriver", "gorm")
-		if err != nil {
-			return "", err
-		}
-		di = sqlite.Dialector{Conn: rd}
-		recordriver.SetResponse("gorm", "select sqlite_version()", &recordriver.Response{
-			Cols: []string{"sqlite_version()"},
-			Data: [][]driver.Value{{"3.30.1"}},
-		})
-	case "mysql":
-		di = mysql.New(mysql.Config{
-			DriverName: "recordriver",
-			DSN:        "gorm",
-		})
-		recordriver.SetResponse("gorm", "SELECT VERSION()", &recordriver.Response{
-			Cols: []string{"VERSION()"},
-			Data: [][]driver.Value{{"8.0.24"}},
-		})
-	case "postgres":
-		di = postgres.New(postgres.Config{
-			DriverName: "recordriver",
-			DSN:        "gorm",
-		})
-	case "sqlserver":
-		di = sqlserver.New(sqlserver.Config{
-			DriverName: "recordriver",
-			DSN:        "gorm",
-		})
-	case "spanner":
-		di = spannergorm.New(spannergorm.Config{
-			DriverName:                 "recordriver",
-			DisableAutoMigrateBatching: true,
-			DSN:                        "gorm",
-		})
-	default:
-		return "", fmt.Errorf("unsupported engine: %s", l.dialect)
-	}
-	cfg := *l.config
-	db, err := gorm.Open(di, &cfg)
+	tables, views, routines, objects := splitModels(models)
+	db, cm, err := l.open()
 	if err != nil {
 		return "", err
 	}
-	if l.dialect != "sqlite" {
-		db.Config.DisableForeignKeyConstraintWhenMigrating = true
+	defer cm.close(db)
+	cm.models = models
+	if err = cm.setupJoinTables(tables...); err != nil {
+		return "", err
 	}
-	for _, cb := range l.beforeAutoMigrate {
-		if err = cb(db); err != nil {
-			return "", err
-		}
+	if err = cm.CreateSchemas(tables, views); err != nil {
+		return "", err
 	}
-	cdb, err := gorm.Open(dialector{Dialector: di}, l.config)
+	enums, err := cm.DefineEnums(tables)
 	if err != nil {
 		return "", err
 	}
-	cm, ok := cdb.Migrator().(*migrator)
-	if !ok {
-		return "", fmt.Errorf("unexpected migrator type: %T", db.Migrator())
+	if err = cm.CreateEnums(enums); err != nil {
+		return "", err
 	}
-	if err = cm.setupJoinTables(tables...); err != nil {
+	if objects, err = orderObjects(objects, cm.Dialector.Name()); err != nil {
+		return "", err
+	}
+	if err = cm.CreateObjects(objects); err != nil {
 		return "", err
 	}
 	orderedTables, err := cm.orderModels(tables...)
 	if err != nil {
 		return "", err
 	}
-	if err = db.AutoMigrate(orderedTables...); err != nil {
+	if err = cm.migrateTables(db, orderedTables); err != nil {
 		return "", err
 	}
-	if err = cm.CreateViews(views); err != nil {
+	if err = cm.CreateIndexes(tables); err != nil {
+		return "", err
+	}
+	if cm.definitions, err = cm.orderDefinitions(views, routines); err != nil {
+		return "", err
+	}
+	if err = cm.CreateViewsAndRoutines(); err != nil {
 		return "", err
 	}
 	if err = cm.CreateTriggers(models); err != nil {
 		return "", err
 	}
-	if !l.config.DisableForeignKeyConstraintWhenMigrating && l.dialect != "sqlite" {
+	// SQLite foreign keys are defined inline, and ClickHouse does not support them.
+	if !l.config.DisableForeignKeyConstraintWhenMigrating && cm.Dialector.Name() != "sqlite" && cm.Dialector.Name() != "clickhouse" {
 		if err = cm.CreateConstraints(tables); err != nil {
 			return "", err
 		}
 	}
-	s, ok := recordriver.Session("gorm")
+	if err = cm.CreateChecks(tables); err != nil {
+		return "", err
+	}
+	switch l.output {
+	case OutputSQL:
+	case OutputHCL:
+		return l.marshalHCL(cm, tables, views, routines, objects)
+	case OutputJSON:
+		return l.marshalJSON(cm, models)
+	default:
+		return "", fmt.Errorf("unsupported output: %s", l.output)
+	}
+	s, ok := recordriver.Session(cm.session)
 	if !ok {
 		return "", errors.New("gorm db session not found")
 	}
@@ -254,21 +327,208 @@ func (l *Loader) Load(models ...any) (string, error) {
 	return buf.String(), nil
 }
 
+// sessionID is used to generate a unique recording session for each load.
+var sessionID atomic.Uint64
+
+// open opens the gorm.DB used for migrating the models, and the migrator used for creating the
+// schema objects that are not handled by gorm. Both record their statements in a new session,
+// which is deleted when the migrator is closed, or when opening fails.
+func (l *Loader) open() (_ *gorm.DB, _ *migrator, err error) {
+	session := fmt.Sprintf("gorm-%d", sessionID.Add(1))
+	defer func() {
+		if err != nil {
+			releaseSession(session)
+		}
+	}()
+	di, err := openDialector(l.dialect, l.version, session)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(l.schemas) > 0 && di.Name() != "postgres" {
+		return nil, nil, fmt.Errorf("schemas are not supported for %s", l.dialect)
+	}
+	db, err := gorm.Open(di, l.gormConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	cm, err := l.migrator(db, di, session)
+	if err != nil {
+		closeDB(db)
+		return nil, nil, err
+	}
+	return db, cm, nil
+}
+
+// releaseSession deletes the given recording session, which the driver does when a connection
+// to the session is closed. The session may have been created before any connection was opened,
+// e.g. by the responses set by the dialect.
+func releaseSession(session string) {
+	db, err := sql.Open("recordriver", session)
+	if err != nil {
+		return
+	}
+	db.Ping()  // nolint: errcheck
+	db.Close() // nolint: errcheck
+}
+
+// migrator prepares the given database for migrating the models, and opens
+// the migrator, which records its statements in the same session.
+func (l *Loader) migrator(db *gorm.DB, di gorm.Dialector, session string) (*migrator, error) {
+	if di.Name() != "sqlite" {
+		db.Config.DisableForeignKeyConstraintWhenMigrating = true
+	}
+	if err := qualifyTables(db, l.schemas); err != nil {
+		return nil, err
+	}
+	for _, cb := range l.beforeAutoMigrate {
+		if err := cb(db); err != nil {
+			return nil, err
+		}
+	}
+	cdb, err := gorm.Open(dialector{Dialector: di, schemas: l.schemas, session: session}, l.gormConfig())
+	if err != nil {
+		return nil, err
+	}
+	if err = qualifyTables(cdb, l.schemas); err != nil {
+		closeDB(cdb)
+		return nil, err
+	}
+	cm, ok := cdb.Migrator().(*migrator)
+	if !ok {
+		closeDB(cdb)
+		return nil, fmt.Errorf("unexpected migrator type: %T", cdb.Migrator())
+	}
+	cm.db = db
+	return cm, nil
+}
+
+// gormConfig returns a copy of the loader configuration to open a gorm.DB with, as gorm.Open
+// modifies it. Only the exported fields are copied, so the copy does not share the schema cache
+// of a configuration that is also used by the application, as the cached models are modified by
+// qualifyTables. The naming strategy of the copy is wrapped by schemaNamer if schemas are set.
+func (l *Loader) gormConfig() *gorm.Config {
+	cfg := &gorm.Config{}
+	src, dst := reflect.ValueOf(l.config).Elem(), reflect.ValueOf(cfg).Elem()
+	for i := range src.NumField() {
+		if src.Type().Field(i).IsExported() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+	if len(l.schemas) > 0 {
+		ns := cfg.NamingStrategy
+		if ns == nil {
+			// The default naming strategy of gorm.Open.
+			ns = gschema.NamingStrategy{IdentifierMaxLength: 64}
+		}
+		cfg.NamingStrategy = &schemaNamer{Namer: ns, tables: make(map[string]string)}
+	}
+	return cfg
+}
+
+// qualifyTables qualifies the table names of the models assigned to a schema. GORM caches
+// the parsed models, therefore, the relationships referencing them use the qualified names.
+// The names GORM derives from these tables, such as index and foreign key names, are derived
+// from the unqualified names by schemaNamer, as they are when the application migrates them.
+func qualifyTables(db *gorm.DB, schemas map[reflect.Type]string) error {
+	for t, name := range schemas {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(reflect.New(t).Interface()); err != nil {
+			return err
+		}
+		if strings.Contains(stmt.Schema.Table, ".") {
+			continue
+		}
+		if ns, ok := db.NamingStrategy.(*schemaNamer); ok {
+			ns.tables[name+"."+stmt.Schema.Table] = stmt.Schema.Table
+		}
+		stmt.Schema.Table = name + "." + stmt.Schema.Table
+	}
+	return nil
+}
+
+// schemaNamer derives the names of the objects of the tables qualified by qualifyTables
+// from their unqualified names.
+type schemaNamer struct {
+	gschema.Namer
+	// tables maps the qualified tables to their original names.
+	tables map[string]string
+}
+
+// table returns the original name of the given table.
+func (n *schemaNamer) table(name string) string {
+	if t, ok := n.tables[name]; ok {
+		return t
+	}
+	return name
+}
+
+func (n *schemaNamer) ColumnName(table, column string) string {
+	return n.Namer.ColumnName(n.table(table), column)
+}
+
+func (n *schemaNamer) CheckerName(table, column string) string {
+	return n.Namer.CheckerName(n.table(table), column)
+}
+
+func (n *schemaNamer) IndexName(table, column string) string {
+	return n.Namer.IndexName(n.table(table), column)
+}
+
+func (n *schemaNamer) UniqueName(table, column string) string {
+	return n.Namer.UniqueName(n.table(table), column)
+}
+
+func (n *schemaNamer) RelationshipFKName(rel gschema.Relationship) string {
+	if t, ok := n.tables[rel.Schema.Table]; ok {
+		rel.Schema = &gschema.Schema{Name: rel.Schema.Name, ModelType: rel.Schema.ModelType, Table: t}
+	}
+	return n.Namer.RelationshipFKName(rel)
+}
+
+// splitModels splits the given models into table-based, view-based and
+// function-based (or procedure-based) models, and schema objects.
+func splitModels(models []any) (tables []any, views []ViewDefiner, routines []any, objects []Object) {
+	for _, obj := range models {
+		switch view := obj.(type) {
+		case ViewDefiner:
+			views = append(views, view)
+		case FunctionDefiner, ProcedureDefiner:
+			routines = append(routines, obj)
+		case Object:
+			objects = append(objects, view)
+		case *Object:
+			objects = append(objects, *view)
+		default:
+			tables = append(tables, obj)
+		}
+	}
+	return tables, views, routines, objects
+}
+
 func (l *Loader) directives(w io.Writer, cm *migrator) error {
-	if len(l.modelPos) > 0 {
-		pos := map[string]string{}
-		for m, p := range l.modelPos {
-			t := "table"
-			if _, v := m.(ViewDefiner); v {
-				t = "view"
-			}
-			pos[fmt.Sprintf("%s[type=%s]", cm.resourceName(m), t)] = p
+	pos := map[string]string{}
+	for m, p := range l.modelPos {
+		r, err := cm.resource(m)
+		if err != nil {
+			return err
 		}
-		for _, r := range slices.Sorted(maps.Keys(pos)) {
-			if _, err := fmt.Fprintln(w, "-- atlas:pos", r, pos[r]); err != nil {
-				return err
-			}
+		pos[r.directive()] = p
+	}
+	deps, err := cm.dependencyDirectives()
+	if err != nil {
+		return err
+	}
+	for _, r := range slices.Sorted(maps.Keys(pos)) {
+		if _, err := fmt.Fprintln(w, "-- atlas:pos", r, pos[r]); err != nil {
+			return err
 		}
+	}
+	for _, r := range slices.Sorted(maps.Keys(deps)) {
+		if _, err := fmt.Fprintln(w, "-- atlas:deps", r, strings.Join(deps[r], " ")); err != nil {
+			return err
+		}
+	}
+	if len(pos) > 0 || len(deps) > 0 {
 		// Add another new line to separate the file directives from the statements.
 		if _, err := fmt.Fprintln(w); err != nil {
 			return err
@@ -277,13 +537,58 @@ func (l *Loader) directives(w io.Writer, cm *migrator) error {
 	return nil
 }
 
+// dependencyDirectives returns the dependencies declared by the views, functions, procedures
+// and triggers of the loaded models, keyed by the directive of the dependent resource.
+func (m *migrator) dependencyDirectives() (map[string][]string, error) {
+	var (
+		dirs = make(map[string][]string)
+		add  = func(r resource, deps []resource) {
+			for _, d := range deps {
+				dirs[r.directive()] = append(dirs[r.directive()], d.directive())
+			}
+		}
+	)
+	for _, d := range m.definitions {
+		add(d.res, d.deps)
+	}
+	for _, model := range m.models {
+		md, ok := model.(interface {
+			Triggers(string) []Trigger
+		})
+		if !ok {
+			continue
+		}
+		for _, t := range md.Triggers(m.Dialector.Name()) {
+			b, deps, err := m.triggerBuilder(model, t)
+			if err != nil {
+				return nil, err
+			}
+			if name := b.triggerName(); name != "" {
+				add(resource{name: name, typ: "trigger"}, deps)
+			}
+		}
+	}
+	return dirs, nil
+}
+
 type migrator struct {
 	gormig.Migrator
 	dialectMigrator gorm.Migrator
+	schemas         map[reflect.Type]string
+	// db is the database the tables are migrated with, which does not share the schema cache of the migrator.
+	db *gorm.DB
+	// models holds the loaded models, which the declared dependencies must be part of.
+	models []any
+	// definitions holds the view-based and function-based models of the load, ordered by their dependencies.
+	definitions []*definition
+	// session is the name of the recordriver session the statements are recorded in.
+	session string
 }
 
 type dialector struct {
 	gorm.Dialector
+	schemas map[reflect.Type]string
+	session string
 }
 
 // Migrator returns a new gorm.Migrator, which can be used to extend the default migrator,
@@ -297,6 +602,23 @@ func (d dialector) Migrator(db *gorm.DB) gorm.Migrator {
 			},
 		},
 		dialectMigrator: d.Dialector.Migrator(db),
+		schemas:         d.schemas,
+		session:         d.session,
+	}
+}
+
+// close closes the connections of the migrator and the given database,
+// which deletes their recording session.
+func (m *migrator) close(db *gorm.DB) {
+	closeDB(db, m.DB)
+}
+
+// closeDB closes the connection pools of the given databases.
+func closeDB(dbs ...*gorm.DB) {
+	for _, db := range dbs {
+		if sdb, err := db.DB(); err == nil {
+			sdb.Close() // nolint: errcheck
+		}
 	}
 }
 
@@ -373,25 +695,248 @@ func (m *migrator) setupJoinTables(models ...any) error {
 	return nil
 }
 
-// CreateViews creates the given "view-based" models
-func (m *migrator) CreateViews(views []ViewDefiner) error {
+// definition is a view-based or function-based model, and the statement that creates it.
+type definition struct {
+	model any
+	res   resource
+	stmt  string
+	// deps holds the dependencies declared by the DependsOn option.
+	deps []resource
+	// nameRe matches the references to the definition in other statements.
+	nameRe *regexp.Regexp
+}
+
+// CreateViewsAndRoutines creates the "view-based" and function-based models ordered by
+// their dependencies, and the indexes of the materialized views.
+func (m *migrator) CreateViewsAndRoutines() error {
+	for _, d := range m.definitions {
+		if err := m.DB.Exec(d.stmt).Error; err != nil {
+			return err
+		}
+		v, ok := d.model.(ViewDefiner)
+		if !ok {
+			continue
+		}
+		for _, idx := range m.modelIndexes(v) {
+			s, err := m.indexStmt(d.res.name, idx)
+			if err != nil {
+				return err
+			}
+			if err := m.DB.Exec(s).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// orderDefinitions orders the given view-based and function-based models by their dependencies,
+// which are either declared by the DependsOn option or, if none are declared, inferred from the
+// references to their names in the statements that create them.
+func (m *migrator) orderDefinitions(views []ViewDefiner, routines []any) ([]*definition, error) {
+	var defs []*definition
+	for _, r := range routines {
+		b := m.buildRoutine(r)
+		if b.createStmt == "" {
+			continue
+		}
+		d := &definition{model: r, res: resource{name: m.routineName(r), typ: routineType(r)}, stmt: b.createStmt}
+		if err := m.resolveDeps(d, b.deps); err != nil {
+			return nil, err
+		}
+		defs = append(defs, d)
+	}
 	for _, v := range views {
-		b := &schemaBuilder{db: m.DB, viewName: m.resourceName(v)}
-		for _, o := range v.ViewDef(m.Dialector.Name()) {
-			o.apply(b)
+		b, err := m.buildView(v)
+		if err != nil {
+			return nil, err
 		}
-		if err := m.DB.Exec(b.createStmt).Error; err != nil {
-			return err
+		r, err := m.resource(v)
+		if err != nil {
+			return nil, err
+		}
+		d := &definition{model: v, res: r, stmt: b.createStmt}
+		if err := m.resolveDeps(d, b.deps); err != nil {
+			return nil, err
+		}
+		defs = append(defs, d)
+	}
+	for _, d := range defs {
+		d.nameRe = referenceRe(d.res.name)
+	}
+	return topoSort(defs, func(d *definition) string { return d.res.String() }, func(d *definition) []string {
+		var deps []string
+		for _, r := range d.deps {
+			deps = append(deps, r.String())
 		}
+		if len(deps) > 0 {
+			return deps
+		}
+		stmt := unquoter.Replace(literalRe.ReplaceAllString(d.stmt, "''"))
+		for _, o := range defs {
+			if o != d && o.nameRe.MatchString(stmt) {
+				deps = append(deps, o.res.String())
+			}
+		}
+		return deps
+	})
+}
+
+// resolveDeps sets the resources of the given declared dependencies on the definition.
+func (m *migrator) resolveDeps(d *definition, deps []any) error {
+	rs, err := m.dependencies(deps)
+	if err != nil {
+		return fmt.Errorf("%s: %w", d.res, err)
 	}
+	d.deps = rs
 	return nil
 }
 
+// dependencies returns the resources of the given models, which must be part of the loaded models.
+func (m *migrator) dependencies(deps []any) ([]resource, error) {
+	rs := make([]resource, 0, len(deps))
+	for _, d := range deps {
+		t := indirect(reflect.TypeOf(d))
+		if !slices.ContainsFunc(m.models, func(x any) bool { return indirect(reflect.TypeOf(x)) == t }) {
+			return nil, fmt.Errorf("dependency %s is not loaded", modelName(d))
+		}
+		r, err := m.resource(d)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// resource returns the schema resource created for the given model.
+func (m *migrator) resource(model any) (resource, error) {
+	switch model := model.(type) {
+	case ViewDefiner:
+		b, err := m.buildView(model)
+		if err != nil {
+			return resource{}, err
+		}
+		if b.materialized {
+			return resource{name: b.viewName, typ: "materialized"}, nil
+		}
+		return resource{name: b.viewName, typ: "view"}, nil
+	case FunctionDefiner, ProcedureDefiner:
+		return resource{name: m.routineName(model), typ: routineType(model)}, nil
+	default:
+		return resource{name: m.resourceName(model), typ: "table"}, nil
+	}
+}
+
+// String returns the resource as it is reported in errors, e.g. "view active_users".
+func (r resource) String() string {
+	return r.typ + " " + r.name
+}
+
+// directive returns the resource as it is referenced by Atlas directives, e.g. "active_users[type=view]".
+func (r resource) directive() string {
+	return fmt.Sprintf("%s[type=%s]", r.name, r.typ)
+}
+
+var (
+	// literalRe matches the string literals of a statement.
+	literalRe = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// unquoter removes the quotes of the identifiers of a statement.
+	unquoter = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "")
+)
+
+// referenceRe returns a regexp that matches the references to the object with the given name
+// in a statement whose string literals and identifier quotes were removed. Only the positions
+// that reference a relation or a routine are matched, such as "FROM name", "JOIN name" or
+// "name(", and not columns or aliases that share the name of the object.
+func referenceRe(name string) *regexp.Regexp {
+	name = regexp.QuoteMeta(name)
+	return regexp.MustCompile(`(?i)\b(?:FROM|JOIN|INTO|UPDATE|CALL)\s+` + name + `(?:$|[^\w$.(])|(?:^|[^\w$.])` + name + `\s*\(`)
+}
+
+// materializedRe matches a CREATE MATERIALIZED VIEW statement.
+var materializedRe = regexp.MustCompile(`(?is)^\s*CREATE\s+MATERIALIZED\s+VIEW\s`)
+
+// buildView applies the options of the given view-based model.
+func (m *migrator) buildView(v ViewDefiner) (*schemaBuilder, error) {
+	var (
+		dialect = m.Dialector.Name()
+		b       = &schemaBuilder{db: m.DB, viewName: m.resourceName(v)}
+	)
+	for _, o := range v.ViewDef(dialect) {
+		o.apply(b)
+	}
+	if materializedRe.MatchString(b.createStmt) {
+		b.materialized = true
+	}
+	switch {
+	case b.materialized && dialect != "postgres":
+		return nil, fmt.Errorf("view %s: materialized views are not supported by %s", b.viewName, dialect)
+	case b.noData && !b.materialized:
+		return nil, fmt.Errorf("view %s: WITH NO DATA is only supported by materialized views", b.viewName)
+	case !b.materialized && len(m.modelIndexes(v)) > 0:
+		return nil, fmt.Errorf("view %s: indexes are only supported by materialized views", b.viewName)
+	}
+	if b.createStmt != "" || b.viewDef == "" {
+		return b, nil
+	}
+	switch {
+	case b.materialized && b.noData:
+		b.createStmt = fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s WITH NO DATA", b.viewName, b.viewDef)
+	case b.materialized:
+		b.createStmt = fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s WITH DATA", b.viewName, b.viewDef)
+	case dialect == "spanner":
+		b.createStmt = fmt.Sprintf("CREATE VIEW %s SQL SECURITY INVOKER AS %s", b.viewName, b.viewDef)
+	default:
+		b.createStmt = fmt.Sprintf("CREATE VIEW %s AS %s", b.viewName, b.viewDef)
+	}
+	return b, nil
+}
+
 func (m *migrator) resourceName(model any) string {
+	var name string
 	if t, ok := model.(interface{ TableName() string }); ok {
-		return t.TableName()
+		name = t.TableName()
+	} else {
+		name = m.DB.NamingStrategy.TableName(indirect(reflect.TypeOf(model)).Name())
+	}
+	if s, ok := m.schemas[indirect(reflect.TypeOf(model))]; ok && !strings.Contains(name, ".") {
+		name = s + "." + name
+	}
+	return name
+}
+
+// CreateSchemas creates the schemas of the given models, which are qualified
+// with the schema name either by the WithSchema option or by their table names.
+func (m *migrator) CreateSchemas(tables []any, views []ViewDefiner) error {
+	if m.Dialector.Name() != "postgres" {
+		return nil
+	}
+	var names []string
+	for _, model := range m.ReorderModels(tables, true) {
+		err := m.RunWithValue(model, func(stmt *gorm.Statement) error {
+			names = append(names, stmt.Schema.Table)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	}
-	return m.DB.NamingStrategy.TableName(indirect(reflect.TypeOf(model)).Name())
+	for _, v := range views {
+		names = append(names, m.resourceName(v))
+	}
+	schemas := make(map[string]bool)
+	for _, n := range names {
+		if s, _, ok := strings.Cut(n, "."); ok && s != "public" {
+			schemas[s] = true
+		}
+	}
+	for _, s := range slices.Sorted(maps.Keys(schemas)) {
+		if err := m.DB.Exec("CREATE SCHEMA IF NOT EXISTS ?", clause.Table{Name: s}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // orderModels places join tables at the end of the list of models (if any),
@@ -439,12 +984,12 @@ func (m *migrator) CreateTriggers(models []any) error {
 			Triggers(string) []Trigger
 		}); ok {
 			for _, trigger := range md.Triggers(m.Dialector.Name()) {
-				schemaBuilder := &schemaBuilder{
-					db: m.DB,
+				stmts, err := m.buildTrigger(model, trigger)
+				if err != nil {
+					return err
 				}
-				for _, opt := range trigger.opts {
-					opt.apply(schemaBuilder)
-					if err := m.DB.Exec(schemaBuilder.createStmt).Error; err != nil {
+				for _, stmt := range stmts {
+					if err := m.DB.Exec(stmt).Error; err != nil {
 						return err
 					}
 				}
@@ -454,6 +999,76 @@ func (m *migrator) CreateTriggers(models []any) error {
 	return nil
 }
 
+// buildTrigger applies the options of the given trigger and returns the statements creating it.
+func (m *migrator) buildTrigger(model any, t Trigger) ([]string, error) {
+	b, _, err := m.triggerBuilder(model, t)
+	if err != nil {
+		return nil, err
+	}
+	return b.triggerStmts()
+}
+
+// triggerBuilder applies the options of the given trigger and resolves its declared dependencies.
+func (m *migrator) triggerBuilder(model any, t Trigger) (*schemaBuilder, []resource, error) {
+	_, onView := model.(ViewDefiner)
+	b := &schemaBuilder{db: m.DB, tableName: m.resourceName(model), onView: onView}
+	for _, opt := range t.opts {
+		opt.apply(b)
+	}
+	deps, err := m.dependencies(b.deps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trigger on %s: %w", b.tableName, err)
+	}
+	return b, deps, nil
+}
+
+// topoSort orders the given nodes such that each node comes after its dependencies, and otherwise
+// keeps their original order. Dependencies that are not in the list are ignored.
+func topoSort[T any](nodes []T, key func(T) string, deps func(T) []string) ([]T, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var (
+		sorted = make([]T, 0, len(nodes))
+		byKey  = make(map[string]T, len(nodes))
+		state  = make(map[string]int, len(nodes))
+		path   []string
+		visit  func(T) error
+	)
+	for _, n := range nodes {
+		byKey[key(n)] = n
+	}
+	visit = func(n T) error {
+		k := key(n)
+		switch state[k] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path[slices.Index(path, k):], k), " -> "))
+		case visited:
+			return nil
+		}
+		state[k] = visiting
+		path = append(path, k)
+		for _, d := range deps(n) {
+			if dn, ok := byKey[d]; ok {
+				if err := visit(dn); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[k] = visited
+		sorted = append(sorted, n)
+		return nil
+	}
+	for _, n := range nodes {
+		if err := visit(n); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
 func indirect(t reflect.Type) reflect.Type {
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
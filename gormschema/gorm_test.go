@@ -1,20 +1,40 @@
 package gormschema_test
 
 import (
+	"cmp"
+	"database/sql/driver"
+	"errors"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"ariga.io/atlas-provider-gorm/gormschema"
+	_ "ariga.io/atlas-provider-gorm/gormschema/clickhouse"
+	"ariga.io/atlas-provider-gorm/internal/testdata/checks"
 	ckmodels "ariga.io/atlas-provider-gorm/internal/testdata/circularfks"
+	"ariga.io/atlas-provider-gorm/internal/testdata/clickhouse"
 	"ariga.io/atlas-provider-gorm/internal/testdata/customjointable"
+	"ariga.io/atlas-provider-gorm/internal/testdata/dependencies"
+	"ariga.io/atlas-provider-gorm/internal/testdata/enums"
+	"ariga.io/atlas-provider-gorm/internal/testdata/functions"
+	"ariga.io/atlas-provider-gorm/internal/testdata/indexes"
+	"ariga.io/atlas-provider-gorm/internal/testdata/materialized"
 	"ariga.io/atlas-provider-gorm/internal/testdata/models"
+	"ariga.io/atlas-provider-gorm/internal/testdata/multischema"
+	"ariga.io/atlas-provider-gorm/internal/testdata/objects"
+	"ariga.io/atlas-provider-gorm/internal/testdata/triggers"
+	"ariga.io/atlas-provider-gorm/internal/testdata/variants"
 	"ariga.io/atlas/sdk/recordriver"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 func TestSQLiteConfig(t *testing.T) {
-	resetSession()
 	l := gormschema.New("sqlite")
 	sql, err := l.Load(
 		models.WorkingAgedUsers{},
@@ -26,18 +46,15 @@ func TestSQLiteConfig(t *testing.T) {
 	)
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/sqlite_default.sql")
-	resetSession()
 	l = gormschema.New("sqlite", gormschema.WithConfig(&gorm.Config{
 		DisableForeignKeyConstraintWhenMigrating: true,
 	}))
 	sql, err = l.Load(models.UserPetHistory{}, models.Pet{}, models.User{})
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/sqlite_no_fk.sql")
-	resetSession()
 }
 
 func TestPostgreSQLConfig(t *testing.T) {
-	resetSession()
 	l := gormschema.New("postgres")
 	sql, err := l.Load(
 		models.WorkingAgedUsers{},
@@ -50,7 +67,6 @@ func TestPostgreSQLConfig(t *testing.T) {
 	)
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/postgresql_default.sql")
-	resetSession()
 	l = gormschema.New("postgres", gormschema.WithConfig(
 		&gorm.Config{
 			DisableForeignKeyConstraintWhenMigrating: true,
@@ -61,7 +77,6 @@ func TestPostgreSQLConfig(t *testing.T) {
 }
 
 func TestMySQLConfig(t *testing.T) {
-	resetSession()
 	l := gormschema.New("mysql")
 	sql, err := l.Load(
 		models.WorkingAgedUsers{},
@@ -74,7 +89,6 @@ func TestMySQLConfig(t *testing.T) {
 	)
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/mysql_default.sql")
-	resetSession()
 	l = gormschema.New("mysql", gormschema.WithConfig(
 		&gorm.Config{
 			DisableForeignKeyConstraintWhenMigrating: true,
@@ -83,7 +97,6 @@ func TestMySQLConfig(t *testing.T) {
 	sql, err = l.Load(ckmodels.Location{}, ckmodels.Event{})
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/mysql_no_fk.sql")
-	resetSession()
 	l = gormschema.New("mysql",
 		gormschema.WithModelPosition(map[any]string{
 			&customjointable.Person{}:              "/internal/testdata/customjointable/models.go:11",
@@ -96,7 +109,6 @@ func TestMySQLConfig(t *testing.T) {
 	sql, err = l.Load(customjointable.Address{}, customjointable.Person{}, customjointable.TopCrowdedAddresses{})
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/mysql_custom_join_table.sql")
-	resetSession()
 	l = gormschema.New("mysql", gormschema.WithModelPosition(map[any]string{
 		&customjointable.Person{}:              "/internal/testdata/customjointable/models.go:11",
 		&customjointable.Address{}:             "/internal/testdata/customjointable/models.go:17",
@@ -106,7 +118,6 @@ func TestMySQLConfig(t *testing.T) {
 	sql, err = l.Load(customjointable.PersonAddress{}, customjointable.Address{}, customjointable.Person{}, customjointable.TopCrowdedAddresses{})
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/mysql_custom_join_table.sql")
-	resetSession()
 	l = gormschema.New("mysql", gormschema.WithModelPosition(map[any]string{
 		&customjointable.Person{}:              "/internal/testdata/customjointable/models.go:11",
 		&customjointable.Address{}:             "/internal/testdata/customjointable/models.go:17",
@@ -118,8 +129,329 @@ func TestMySQLConfig(t *testing.T) {
 	requireEqualContent(t, sql, "testdata/mysql_custom_join_table.sql") // position of tables should not matter
 }
 
+func TestDialectVersion(t *testing.T) {
+	for _, tt := range []struct {
+		dialect, version, golden string
+	}{
+		{dialect: "mysql", version: "5.7", golden: "testdata/mysql_default.sql"},
+		{dialect: "mysql", version: "5.5", golden: "testdata/mysql_5.5_default.sql"},
+		{dialect: "mariadb", golden: "testdata/mariadb_default.sql"},
+		{dialect: "mariadb", version: "10.6", golden: "testdata/mariadb_default.sql"},
+	} {
+		l := gormschema.New(tt.dialect, gormschema.WithDialectVersion(tt.version))
+		sql, err := l.Load(
+			models.WorkingAgedUsers{},
+			ckmodels.Location{},
+			ckmodels.Event{},
+			models.UserPetHistory{},
+			models.User{},
+			models.Pet{},
+			models.TopPetOwner{},
+		)
+		require.NoError(t, err)
+		requireEqualContent(t, sql, tt.golden)
+	}
+	_, err := gormschema.New("postgres", gormschema.WithDialectVersion("15")).Load(models.User{})
+	require.EqualError(t, err, "dialect version is not supported for postgres")
+}
+
+// registerDialects registers the dialects of TestRegisterDialect once, as
+// the registry is global and the test may run multiple times (-count).
+var registerDialects sync.Once
+
+func TestRegisterDialect(t *testing.T) {
+	registerDialects.Do(func() {
+		gormschema.RegisterDialect("legacymysql", func(c *gormschema.DialectConn) (gorm.Dialector, error) {
+			c.SetResponse("SELECT VERSION()", []string{"VERSION()"}, []driver.Value{cmp.Or(c.Version, "5.5.0")})
+			return mysql.New(mysql.Config{DriverName: c.DriverName, DSN: c.DSN}), nil
+		})
+		gormschema.RegisterDialect("libsql", func(c *gormschema.DialectConn) (gorm.Dialector, error) {
+			db, err := c.Open()
+			if err != nil {
+				return nil, err
+			}
+			c.SetResponse("select sqlite_version()", []string{"sqlite_version()"}, []driver.Value{"3.45.1"})
+			return sqlite.Dialector{Conn: db}, nil
+		})
+	})
+	require.Contains(t, gormschema.Dialects(), "legacymysql")
+	require.Panics(t, func() {
+		gormschema.RegisterDialect("legacymysql", func(*gormschema.DialectConn) (gorm.Dialector, error) { return nil, nil })
+	})
+	sql, err := gormschema.New("legacymysql").Load(
+		models.WorkingAgedUsers{},
+		ckmodels.Location{},
+		ckmodels.Event{},
+		models.UserPetHistory{},
+		models.User{},
+		models.Pet{},
+		models.TopPetOwner{},
+	)
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/mysql_5.5_default.sql")
+	// Dialects based on a built-in driver behave like it, e.g. SQLite foreign keys are defined inline.
+	sql, err = gormschema.New("libsql").Load(ckmodels.Event{}, ckmodels.Location{})
+	require.NoError(t, err)
+	expected, err := gormschema.New("sqlite").Load(ckmodels.Event{}, ckmodels.Location{})
+	require.NoError(t, err)
+	require.Equal(t, expected, sql)
+	_, err = gormschema.New("unknown").Load(models.User{})
+	require.EqualError(t, err, "unsupported engine: unknown")
+}
+
+var (
+	// registerSessionDialect registers the dialect of TestLoadReleasesSession once.
+	registerSessionDialect sync.Once
+	// lastSession holds the recording session the dialect was last opened on.
+	lastSession string
+)
+
+func TestLoadReleasesSession(t *testing.T) {
+	registerSessionDialect.Do(func() {
+		gormschema.RegisterDialect("sessionmysql", func(c *gormschema.DialectConn) (gorm.Dialector, error) {
+			lastSession = c.DSN
+			c.SetResponse("SELECT VERSION()", []string{"VERSION()"}, []driver.Value{"8.0.24"})
+			if c.Version == "broken" {
+				return nil, errors.New("broken dialect")
+			}
+			return mysql.New(mysql.Config{DriverName: c.DriverName, DSN: c.DSN}), nil
+		})
+	})
+	_, err := gormschema.New("sessionmysql").Load(models.User{})
+	require.NoError(t, err)
+	_, ok := recordriver.Session(lastSession)
+	require.False(t, ok)
+	// The session is released when opening the loader fails.
+	_, err = gormschema.New("sessionmysql", gormschema.WithDialectVersion("broken")).Load(models.User{})
+	require.EqualError(t, err, "broken dialect")
+	_, ok = recordriver.Session(lastSession)
+	require.False(t, ok)
+	_, err = gormschema.New("sessionmysql", gormschema.WithSchema("crm", models.User{})).Load(models.User{})
+	require.EqualError(t, err, "schemas are not supported for sessionmysql")
+	_, ok = recordriver.Session(lastSession)
+	require.False(t, ok)
+}
+
+func TestSchemas(t *testing.T) {
+	// The configuration is shared with the application, whose cached models are not qualified.
+	cfg := &gorm.Config{DisableAutomaticPing: true}
+	app, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), cfg)
+	require.NoError(t, err)
+	require.NoError(t, app.Statement.Parse(&multischema.Account{}))
+	l := gormschema.New("postgres",
+		gormschema.WithConfig(cfg),
+		gormschema.WithSchema("crm", multischema.Account{}, multischema.AccountBalance{}),
+		gormschema.WithSchema("billing", multischema.Payment{}),
+		gormschema.WithModelPosition(map[any]string{
+			&multischema.Account{}:        "/internal/testdata/multischema/models.go:10",
+			&multischema.Invoice{}:        "/internal/testdata/multischema/models.go:16",
+			&multischema.AccountBalance{}: "/internal/testdata/multischema/models.go:34",
+		}),
+	)
+	sql, err := l.Load(multischema.Account{}, multischema.Invoice{}, multischema.Tag{}, multischema.AccountBalance{}, multischema.Payment{})
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/postgresql_schemas.sql")
+	stmt := &gorm.Statement{DB: app}
+	require.NoError(t, stmt.Parse(&multischema.Account{}))
+	require.Equal(t, "accounts", stmt.Schema.Table)
+	l = gormschema.New("postgres",
+		gormschema.WithSchema("crm", multischema.Account{}, multischema.AccountBalance{}),
+		gormschema.WithOutput(gormschema.OutputHCL),
+	)
+	hcl, err := l.Load(multischema.Account{}, multischema.Invoice{}, multischema.Tag{}, multischema.AccountBalance{})
+	require.NoError(t, err)
+	requireEqualContent(t, hcl, "testdata/postgresql_schemas.hcl")
+	info, err := gormschema.New("postgres", gormschema.WithSchema("crm", multischema.Account{})).LoadSchema(multischema.Account{}, multischema.Invoice{})
+	require.NoError(t, err)
+	require.Equal(t, "crm", info.Tables[0].Schema)
+	require.Equal(t, "accounts", info.Tables[0].Name)
+	require.Equal(t, "billing", info.Tables[1].Schema)
+	require.Equal(t, "invoices", info.Tables[1].Name)
+	require.Equal(t, "crm.accounts", info.Tables[1].Constraints[0].RefTable)
+	_, err = gormschema.New("mysql", gormschema.WithSchema("crm", multischema.Account{})).Load(multischema.Account{})
+	require.EqualError(t, err, "schemas are not supported for mysql")
+}
+
+func TestIndexes(t *testing.T) {
+	requireGoldens(t, "indexes", []string{"postgres", "mysql", "sqlite", "sqlserver", "spanner"}, []any{indexes.Customer{}})
+	hcl, err := gormschema.New("postgres", gormschema.WithOutput(gormschema.OutputHCL)).Load(indexes.Customer{})
+	require.NoError(t, err)
+	requireEqualContent(t, hcl, "testdata/postgresql_indexes.hcl")
+	info, err := gormschema.New("postgres").LoadSchema(indexes.Customer{})
+	require.NoError(t, err)
+	require.Equal(t, &gormschema.IndexInfo{
+		Name:    "idx_customers_country",
+		Columns: []string{"country"},
+		Include: []string{"name"},
+		Type:    "BTREE",
+	}, info.Tables[0].Indexes[2])
+	_, err = gormschema.New("sqlserver").Load(indexes.Order{})
+	require.EqualError(t, err, `index "idx_orders_reference" on orders: expressions are not supported by sqlserver`)
+}
+
+func TestChecks(t *testing.T) {
+	requireGoldens(t, "checks", []string{"postgres", "mysql", "sqlite", "sqlserver", "spanner"}, []any{checks.Product{}})
+	info, err := gormschema.New("postgres").LoadSchema(checks.Product{})
+	require.NoError(t, err)
+	require.Equal(t, []*gormschema.ConstraintInfo{
+		{Name: "chk_products_discount", Type: gormschema.ConstraintCheck, Expr: "discount_price >= 0 AND discount_price < price"},
+		{Name: "chk_products_sku", Type: gormschema.ConstraintCheck, Expr: "char_length(sku) = 8"},
+	}, info.Tables[0].Constraints)
+}
+
+func TestTriggers(t *testing.T) {
+	requireGoldens(t, "triggers", []string{"mysql", "sqlite"}, []any{triggers.Pet{}, triggers.PetHistory{}})
+	requireGoldens(t, "triggers", []string{"sqlserver"}, []any{triggers.Pet{}, triggers.PetHistory{}, triggers.Toy{}})
+	requireGoldens(t, "triggers", []string{"postgres"}, []any{triggers.Pet{}, triggers.PetHistory{}, triggers.Toy{}, triggers.Category{}})
+	_, err := gormschema.New("mysql").Load(triggers.Toy{})
+	require.EqualError(t, err, `trigger "trg_toys_updated_at" on toys: multiple events are not supported by mysql`)
+	_, err = gormschema.New("spanner").Load(triggers.Toy{})
+	require.EqualError(t, err, `trigger "trg_toys_updated_at" on toys: triggers are not supported by spanner`)
+	_, err = gormschema.New("sqlserver").Load(rowTrigger{})
+	require.EqualError(t, err, `trigger "trg_row_triggers" on row_triggers: row-level triggers are not supported by sqlserver`)
+	_, err = gormschema.New("sqlite").Load(insteadOfTrigger{})
+	require.EqualError(t, err, `trigger "trg_instead_of_triggers" on instead_of_triggers: INSTEAD OF triggers on tables are not supported by sqlite`)
+	sql, err := gormschema.New("sqlite").Load(rowTrigger{}, insteadOfView{})
+	require.NoError(t, err)
+	require.Contains(t, sql, "CREATE TRIGGER `trg_instead_of_views`\nINSTEAD OF INSERT ON `instead_of_views`")
+	// The trigger function is created in the schema of the table.
+	sql, err = gormschema.New("postgres", gormschema.WithSchema("inventory", triggers.Toy{})).Load(triggers.Toy{})
+	require.NoError(t, err)
+	require.Contains(t, sql, `CREATE OR REPLACE FUNCTION "inventory"."trg_toys_updated_at_func"()`)
+	require.Contains(t, sql, `EXECUTE FUNCTION "inventory"."trg_toys_updated_at_func"()`)
+}
+
+type rowTrigger struct {
+	ID uint
+}
+
+func (rowTrigger) Triggers(string) []gormschema.Trigger {
+	return []gormschema.Trigger{
+		gormschema.NewTrigger(
+			gormschema.TriggerName("trg_row_triggers"),
+			gormschema.TriggerTime(gormschema.TriggerAfter),
+			gormschema.TriggerEvents(gormschema.TriggerInsert),
+			gormschema.ForEachRow(),
+			gormschema.TriggerBody("SELECT 1"),
+		),
+	}
+}
+
+type insteadOfTrigger struct {
+	ID uint
+}
+
+func (insteadOfTrigger) Triggers(string) []gormschema.Trigger {
+	return []gormschema.Trigger{insteadOfInsert("trg_instead_of_triggers")}
+}
+
+type insteadOfView struct {
+	ID uint
+}
+
+func (insteadOfView) ViewDef(string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{gormschema.CreateStmt("CREATE VIEW instead_of_views AS SELECT id FROM row_triggers")}
+}
+
+func (insteadOfView) Triggers(string) []gormschema.Trigger {
+	return []gormschema.Trigger{insteadOfInsert("trg_instead_of_views")}
+}
+
+func insteadOfInsert(name string) gormschema.Trigger {
+	return gormschema.NewTrigger(
+		gormschema.TriggerName(name),
+		gormschema.TriggerTime(gormschema.TriggerInsteadOf),
+		gormschema.TriggerEvents(gormschema.TriggerInsert),
+		gormschema.ForEachRow(),
+		gormschema.TriggerBody("INSERT INTO row_triggers (id) VALUES (NEW.id)"),
+	)
+}
+
+func TestFunctions(t *testing.T) {
+	requireGoldens(t, "functions", []string{"postgres", "mysql", "sqlserver"},
+		[]any{functions.Pet{}, functions.PetHistory{}, functions.LogPetHistory{}, functions.ArchivePets{}},
+		gormschema.WithModelPosition(map[any]string{
+			&functions.Pet{}:           "/internal/testdata/functions/models.go:11",
+			&functions.LogPetHistory{}: "/internal/testdata/functions/models.go:39",
+			&functions.ArchivePets{}:   "/internal/testdata/functions/models.go:61",
+		}),
+	)
+	info, err := gormschema.New("postgres").LoadSchema(functions.Pet{}, functions.LogPetHistory{}, functions.ArchivePets{})
+	require.NoError(t, err)
+	require.Len(t, info.Tables, 1)
+	require.Len(t, info.Functions, 2)
+	require.Equal(t, "log_pet_history", info.Functions[0].Name)
+	require.Equal(t, "function", info.Functions[0].Type)
+	require.Equal(t, "archive_pets", info.Functions[1].Name)
+	require.Equal(t, "procedure", info.Functions[1].Type)
+}
+
+func TestMaterializedViews(t *testing.T) {
+	l := gormschema.New("postgres", gormschema.WithModelPosition(map[any]string{
+		&materialized.Order{}:         "/internal/testdata/materialized/models.go:9",
+		&materialized.CustomerTotal{}: "/internal/testdata/materialized/models.go:15",
+		&materialized.LargeOrder{}:    "/internal/testdata/materialized/models.go:35",
+	}))
+	sql, err := l.Load(materialized.Order{}, materialized.CustomerTotal{}, materialized.LargeOrder{})
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/postgresql_materialized.sql")
+	hcl, err := gormschema.New("postgres", gormschema.WithOutput(gormschema.OutputHCL)).Load(materialized.Order{}, materialized.CustomerTotal{})
+	require.NoError(t, err)
+	requireEqualContent(t, hcl, "testdata/postgresql_materialized.hcl")
+	info, err := gormschema.New("postgres").LoadSchema(materialized.Order{}, materialized.CustomerTotal{})
+	require.NoError(t, err)
+	require.True(t, info.Views[0].Materialized)
+	require.Equal(t, []*gormschema.IndexInfo{{Name: "idx_customer_totals_customer_id", Unique: true, Columns: []string{"customer_id"}}}, info.Views[0].Indexes)
+	for _, dialect := range []string{"mysql", "sqlite", "sqlserver"} {
+		_, err = gormschema.New(dialect).Load(materialized.Order{}, materialized.CustomerTotal{})
+		require.EqualError(t, err, "view customer_totals: materialized views are not supported by "+dialect)
+	}
+}
+
+func TestEnums(t *testing.T) {
+	requireGoldens(t, "enums", []string{"postgres", "mysql", "sqlite", "sqlserver"}, []any{enums.Ticket{}})
+	hcl, err := gormschema.New("postgres", gormschema.WithOutput(gormschema.OutputHCL)).Load(enums.Ticket{})
+	require.NoError(t, err)
+	requireEqualContent(t, hcl, "testdata/postgresql_enums.hcl")
+	info, err := gormschema.New("postgres").LoadSchema(enums.Ticket{})
+	require.NoError(t, err)
+	require.Equal(t, []*gormschema.EnumInfo{{Name: "ticket_status", Values: []string{"open", "pending", "closed"}}}, info.Enums)
+}
+
+func TestObjects(t *testing.T) {
+	sql, err := gormschema.New("postgres").Load(objects.Objects[0], objects.Objects[1], objects.Objects[2], objects.Customer{}, objects.Invoice{})
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/postgresql_objects.sql")
+	info, err := gormschema.New("sqlserver").LoadSchema(objects.Objects[0], objects.Objects[1], objects.Objects[2], objects.Customer{})
+	require.NoError(t, err)
+	require.Equal(t, []*gormschema.ObjectInfo{{Kind: gormschema.ObjectSequence, Name: "invoice_number_seq", Stmt: "CREATE SEQUENCE invoice_number_seq START WITH 1000"}}, info.Objects)
+	_, err = gormschema.New("postgres").Load(objects.Objects[0], objects.Customer{})
+	require.EqualError(t, err, "domain email: missing dependency citext")
+	_, err = gormschema.New("postgres").Load(
+		gormschema.NewObject(gormschema.ObjectDomain, "a", gormschema.ObjectStmt("postgres", "CREATE DOMAIN a AS b"), gormschema.ObjectDependsOn("b")),
+		gormschema.NewObject(gormschema.ObjectDomain, "b", gormschema.ObjectStmt("postgres", "CREATE DOMAIN b AS a"), gormschema.ObjectDependsOn("a")),
+	)
+	require.EqualError(t, err, "dependency cycle: a -> b -> a")
+}
+
+func TestDependencies(t *testing.T) {
+	requireGoldens(t, "dependencies", []string{"postgres", "sqlite"}, []any{
+		dependencies.CustomerCount{}, dependencies.TopCustomer{}, dependencies.ActiveCustomer{},
+		dependencies.CustomerTier{}, dependencies.Customer{}, dependencies.CustomerLog{},
+	})
+	_, err := gormschema.New("postgres").Load(dependencies.Ping{}, dependencies.Pong{})
+	require.EqualError(t, err, "dependency cycle: view pings -> view pongs -> view pings")
+	// Columns and aliases named after other views are not dependencies.
+	sql, err := gormschema.New("postgres").Load(dependencies.Customer{}, dependencies.CustomerLog{}, dependencies.Score{}, dependencies.Tier{})
+	require.NoError(t, err)
+	require.Less(t, strings.Index(sql, "CREATE VIEW score"), strings.Index(sql, "CREATE VIEW tier"))
+	_, err = gormschema.New("postgres").Load(dependencies.CustomerCount{}, dependencies.Customer{}, dependencies.CustomerLog{})
+	require.EqualError(t, err, "function customer_count: dependency dependencies.TopCustomer is not loaded")
+	_, err = gormschema.New("postgres").Load(dependencies.Customer{})
+	require.EqualError(t, err, "trigger on customers: dependency dependencies.CustomerLog is not loaded")
+}
+
 func TestSQLServerConfig(t *testing.T) {
-	resetSession()
 	l := gormschema.New("sqlserver", gormschema.WithStmtDelimiter("\nGO"))
 	sql, err := l.Load(
 		models.WorkingAgedUsers{},
@@ -132,7 +464,6 @@ func TestSQLServerConfig(t *testing.T) {
 	)
 	require.NoError(t, err)
 	requireEqualContent(t, sql, "testdata/sqlserver_default.sql")
-	resetSession()
 	l = gormschema.New("sqlserver",
 		gormschema.WithStmtDelimiter("\nGO"),
 		gormschema.WithConfig(
@@ -144,10 +475,242 @@ func TestSQLServerConfig(t *testing.T) {
 	requireEqualContent(t, sql, "testdata/sqlserver_no_fk.sql")
 }
 
-func resetSession() {
-	sess, ok := recordriver.Session("gorm")
-	if ok {
-		sess.Statements = nil
+func TestSpannerConfig(t *testing.T) {
+	l := gormschema.New("spanner")
+	sql, err := l.Load(
+		models.WorkingAgedUsers{},
+		ckmodels.Location{},
+		ckmodels.Event{},
+		models.UserPetHistory{},
+		models.User{},
+		models.Pet{},
+		models.TopPetOwner{},
+	)
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/spanner_default.sql")
+	l = gormschema.New("spanner", gormschema.WithConfig(
+		&gorm.Config{
+			DisableForeignKeyConstraintWhenMigrating: true,
+		}))
+	sql, err = l.Load(ckmodels.Location{}, ckmodels.Event{})
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/spanner_no_fk.sql")
+	l = gormschema.New("spanner", gormschema.WithModelPosition(map[any]string{
+		&customjointable.Person{}:              "/internal/testdata/customjointable/models.go:11",
+		&customjointable.Address{}:             "/internal/testdata/customjointable/models.go:17",
+		&customjointable.PersonAddress{}:       "/internal/testdata/customjointable/models.go:22",
+		&customjointable.TopCrowdedAddresses{}: "/internal/testdata/customjointable/models.go:29",
+	}))
+	sql, err = l.Load(customjointable.Address{}, customjointable.PersonAddress{}, customjointable.Person{}, customjointable.TopCrowdedAddresses{})
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/spanner_custom_join_table.sql")
+}
+
+func TestPostgresVariants(t *testing.T) {
+	requireGoldens(t, "default", []string{"cockroach", "yugabyte"}, []any{variants.Account{}, variants.Transfer{}, indexes.Customer{}})
+	// Triggers, functions and procedures are rejected by the CockroachDB versions that do not support them.
+	_, err := gormschema.New("cockroach", gormschema.WithDialectVersion("24.2")).Load(triggers.Toy{})
+	require.EqualError(t, err, `triggers are not supported by cockroach before version 24.3: CREATE OR REPLACE FUNCTION "trg_toys_updated_at_func"()`)
+	_, err = gormschema.New("cockroach", gormschema.WithDialectVersion("23.1")).Load(functions.Pet{}, functions.ArchivePets{})
+	require.EqualError(t, err, `procedures are not supported by cockroach before version 23.2: CREATE PROCEDURE archive_pets(before timestamptz)`)
+	_, err = gormschema.New("cockroach", gormschema.WithDialectVersion("23.1")).Load(models.User{}, sqlFunction{}, plpgsqlFunction{})
+	require.EqualError(t, err, `PL/pgSQL functions are not supported by cockroach before version 23.2: CREATE FUNCTION adult_count() RETURNS bigint AS $$`)
+	_, err = gormschema.New("cockroach", gormschema.WithDialectVersion("22.1")).Load(models.User{}, sqlFunction{})
+	require.EqualError(t, err, `functions are not supported by cockroach before version 22.2: CREATE FUNCTION user_count() RETURNS bigint LANGUAGE SQL AS $$ SELECT count(*) FROM users $$`)
+	for _, v := range []string{"", "24.3.1"} {
+		_, err = gormschema.New("cockroach", gormschema.WithDialectVersion(v)).Load(triggers.Toy{}, functions.Pet{}, functions.LogPetHistory{}, functions.ArchivePets{})
+		require.NoError(t, err)
+	}
+	_, err = gormschema.New("cockroach", gormschema.WithDialectVersion("22.1")).Load(models.User{}, replacedView{})
+	require.NoError(t, err)
+	_, err = gormschema.New("yugabyte").Load(gistIndex{})
+	require.EqualError(t, err, `GiST, BRIN and SP-GiST indexes are not supported by yugabyte: CREATE INDEX "idx_gist_indices_name" ON "gist_indices" USING GIST ("name")`)
+	_, err = gormschema.New("cockroach", gormschema.WithDialectVersion("v23")).Load(variants.Account{})
+	require.EqualError(t, err, `cockroach: invalid version "v23"`)
+	_, err = gormschema.New("yugabyte", gormschema.WithDialectVersion("2.20")).Load(variants.Account{})
+	require.EqualError(t, err, "dialect version is not supported for yugabyte")
+}
+
+type replacedView struct{}
+
+func (replacedView) ViewDef(string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{
+		gormschema.CreateStmt("CREATE OR REPLACE VIEW adults AS SELECT * FROM users WHERE age >= 18"),
+	}
+}
+
+type sqlFunction struct{}
+
+func (sqlFunction) FunctionDef(string) []gormschema.FunctionOption {
+	return []gormschema.FunctionOption{
+		gormschema.CreateStmt("CREATE FUNCTION user_count() RETURNS bigint LANGUAGE SQL AS $$ SELECT count(*) FROM users $$"),
+	}
+}
+
+type plpgsqlFunction struct{}
+
+func (plpgsqlFunction) FunctionDef(string) []gormschema.FunctionOption {
+	return []gormschema.FunctionOption{
+		gormschema.CreateStmt("CREATE FUNCTION adult_count() RETURNS bigint AS $$\nBEGIN\n\tRETURN (SELECT count(*) FROM users WHERE age >= 18);\nEND;\n$$ LANGUAGE plpgsql"),
+	}
+}
+
+type gistIndex struct {
+	ID   uint
+	Name string
+}
+
+func (gistIndex) Indexes(string) []gormschema.Index {
+	return []gormschema.Index{
+		gormschema.NewIndex("idx_gist_indices_name", gormschema.IndexColumns("name"), gormschema.IndexMethod("GIST")),
+	}
+}
+
+func TestClickHouseConfig(t *testing.T) {
+	sql, err := gormschema.New("clickhouse").Load(clickhouse.Event{}, clickhouse.PageView{}, clickhouse.Session{})
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/clickhouse_default.sql")
+	_, err = gormschema.New("clickhouse").Load(clickhouse.Event{}, noEngine{})
+	require.EqualError(t, err, "engine of gormschema_test.noEngine: missing name")
+	_, err = gormschema.New("clickhouse").Load(indexedEvent{})
+	require.EqualError(t, err, `index "idx_indexed_events_name" on indexed_events: indexes are not supported by clickhouse`)
+}
+
+type indexedEvent struct {
+	ID   uint64
+	Name string
+}
+
+func (indexedEvent) Engine(string) gormschema.Engine {
+	return gormschema.NewEngine("MergeTree()", gormschema.EngineOrderBy("id"))
+}
+
+func (indexedEvent) Indexes(string) []gormschema.Index {
+	return []gormschema.Index{
+		gormschema.NewIndex("idx_indexed_events_name", gormschema.IndexColumns("name")),
+	}
+}
+
+type noEngine struct {
+	ID uint64
+}
+
+func (noEngine) Engine(string) gormschema.Engine {
+	return gormschema.NewEngine("", gormschema.EngineOrderBy("id"))
+}
+
+func TestHCLOutput(t *testing.T) {
+	for dialect, golden := range map[string]string{
+		"postgres": "testdata/postgresql_default.hcl",
+		"sqlite":   "testdata/sqlite_default.hcl",
+	} {
+		l := gormschema.New(dialect, gormschema.WithOutput(gormschema.OutputHCL))
+		hcl, err := l.Load(
+			models.WorkingAgedUsers{},
+			ckmodels.Location{},
+			ckmodels.Event{},
+			models.UserPetHistory{},
+			models.User{},
+			models.TopPetOwner{},
+		)
+		require.NoError(t, err)
+		requireEqualContent(t, hcl, golden)
+	}
+	l := gormschema.New("mysql", gormschema.WithOutput(gormschema.OutputHCL), gormschema.WithHCLSchema("app"))
+	hcl, err := l.Load(ckmodels.Location{}, ckmodels.Event{}, models.UserPetHistory{}, models.User{})
+	require.NoError(t, err)
+	requireEqualContent(t, hcl, "testdata/mysql_default.hcl")
+	_, err = l.Load(models.User{}, models.WorkingAgedUsers{})
+	require.EqualError(t, err, `HCL output does not support views for dialect "mysql"`)
+	_, err = l.Load(models.User{}, models.Pet{})
+	require.EqualError(t, err, "HCL output does not support triggers")
+	_, err = gormschema.New("mysql", gormschema.WithOutput(gormschema.OutputHCL)).Load(models.User{})
+	require.EqualError(t, err, `HCL output requires a schema name for dialect "mysql"`)
+	_, err = gormschema.New("sqlserver", gormschema.WithOutput(gormschema.OutputHCL)).Load(models.User{})
+	require.EqualError(t, err, `HCL output is not supported for dialect "sqlserver"`)
+}
+
+func TestLoadSchema(t *testing.T) {
+	l := gormschema.New("mysql", gormschema.WithModelPosition(map[any]string{
+		&models.User{}:             "/internal/testdata/models/user.go:9",
+		&models.WorkingAgedUsers{}: "/internal/testdata/models/user.go:23",
+	}))
+	info, err := l.LoadSchema(models.User{}, models.Pet{}, models.WorkingAgedUsers{})
+	require.NoError(t, err)
+	require.Len(t, info.Tables, 4)
+	users := info.Tables[0]
+	require.Equal(t, "users", users.Name)
+	require.Equal(t, "models.User", users.Model)
+	require.Equal(t, "/internal/testdata/models/user.go:9", users.Pos)
+	require.Equal(t, []string{"id"}, users.PrimaryKey)
+	require.Equal(t, &gormschema.ColumnInfo{
+		Field:         "ID",
+		Name:          "id",
+		Type:          "bigint unsigned",
+		PrimaryKey:    true,
+		AutoIncrement: true,
+	}, users.Columns[0])
+	require.Equal(t, &gormschema.IndexInfo{Name: "idx_users_deleted_at", Columns: []string{"deleted_at"}}, users.Indexes[0])
+	pets := info.Tables[3]
+	require.Equal(t, "pets", pets.Name)
+	require.Equal(t, []*gormschema.ConstraintInfo{
+		{Name: "fk_users_pets", Type: gormschema.ConstraintForeignKey, Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+	}, pets.Constraints)
+	require.Len(t, info.Views, 1)
+	require.Equal(t, "working_aged_users", info.Views[0].Name)
+	require.Equal(t, "/internal/testdata/models/user.go:23", info.Views[0].Pos)
+	require.Equal(t, "CREATE VIEW working_aged_users AS SELECT name, age FROM `users` WHERE age BETWEEN 18 AND 65", info.Views[0].Stmt)
+	require.Len(t, info.Triggers, 2)
+	require.Equal(t, "trg_insert_user_pet_history", info.Triggers[0].Name)
+	require.Equal(t, "pets", info.Triggers[0].Table)
+	require.Equal(t, "models.Pet", info.Triggers[0].Model)
+	l = gormschema.New("postgres", gormschema.WithOutput(gormschema.OutputJSON))
+	sql, err := l.Load(
+		ckmodels.Location{},
+		ckmodels.Event{},
+		models.UserPetHistory{},
+		models.User{},
+		models.Pet{},
+		models.TopPetOwner{},
+	)
+	require.NoError(t, err)
+	requireEqualContent(t, sql, "testdata/postgresql_default.json")
+}
+
+func TestConcurrentLoad(t *testing.T) {
+	dialects := []string{"mysql", "sqlite", "postgres", "sqlserver", "spanner"}
+	expected := make(map[string]string)
+	for _, d := range dialects {
+		sql, err := gormschema.New(d).Load(ckmodels.Event{}, ckmodels.Location{}, models.WorkingAgedUsers{})
+		require.NoError(t, err)
+		expected[d] = sql
+	}
+	var wg sync.WaitGroup
+	for range 5 {
+		for _, d := range dialects {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sql, err := gormschema.New(d).Load(ckmodels.Event{}, ckmodels.Location{}, models.WorkingAgedUsers{})
+				assert.NoError(t, err)
+				assert.Equal(t, expected[d], sql)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// requireGoldens loads the models using each of the given dialects, and compares the output
+// with the golden file of the dialect, e.g. testdata/postgresql_<name>.sql for postgres.
+func requireGoldens(t *testing.T, name string, dialects []string, models []any, opts ...gormschema.Option) {
+	for _, dialect := range dialects {
+		prefix := dialect
+		if dialect == "postgres" {
+			prefix = "postgresql"
+		}
+		sql, err := gormschema.New(dialect, opts...).Load(models...)
+		require.NoError(t, err, dialect)
+		requireEqualContent(t, sql, "testdata/"+prefix+"_"+name+".sql")
 	}
 }
 
@@ -0,0 +1,448 @@
+package gormschema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"gorm.io/gorm"
+	gschema "gorm.io/gorm/schema"
+)
+
+// hclDialect holds the dialect-specific parts needed to convert a GORM schema into an Atlas HCL document.
+type hclDialect struct {
+	// schema is the default name of the schema that holds the tables and views.
+	// An empty name means it must be set using the WithHCLSchema option.
+	schema string
+	// parseType parses the column type returned by the GORM dialector.
+	parseType func(string) (schema.Type, error)
+	// autoIncrement is the attribute attached to auto-incremented columns.
+	// A nil value means the column type already expresses it (e.g. Postgres serials).
+	autoIncrement schema.Attr
+	// predicate returns the attribute of a partial index.
+	predicate func(string) schema.Attr
+	// indexType returns the attribute of an index method.
+	indexType func(string) schema.Attr
+	// include returns the attribute of the non-key columns of an index.
+	include func([]*schema.Column) schema.Attr
+	// noViews indicates the HCL marshaler of the dialect does not support views.
+	noViews   bool
+	marshaler schemahcl.Marshaler
+}
+
+var (
+	hclDialects = map[string]hclDialect{
+		"mysql": {
+			parseType:     mysql.ParseType,
+			autoIncrement: &mysql.AutoIncrement{},
+			indexType:     func(t string) schema.Attr { return &mysql.IndexType{T: t} },
+			noViews:       true,
+			marshaler:     mysql.MarshalHCL,
+		},
+		"mariadb": {
+			parseType:     mysql.ParseType,
+			autoIncrement: &mysql.AutoIncrement{},
+			indexType:     func(t string) schema.Attr { return &mysql.IndexType{T: t} },
+			noViews:       true,
+			marshaler:     mysql.MarshalHCL,
+		},
+		"postgres": {
+			schema:    "public",
+			parseType: postgres.ParseType,
+			predicate: func(p string) schema.Attr { return &postgres.IndexPredicate{P: p} },
+			indexType: func(t string) schema.Attr { return &postgres.IndexType{T: t} },
+			include:   func(c []*schema.Column) schema.Attr { return &postgres.IndexInclude{Columns: c} },
+			marshaler: postgres.MarshalHCL,
+		},
+		"sqlite": {
+			schema:        "main",
+			parseType:     sqlite.ParseType,
+			autoIncrement: &sqlite.AutoIncrement{},
+			predicate:     func(p string) schema.Attr { return &sqlite.IndexPredicate{P: p} },
+			marshaler:     sqlite.MarshalHCL,
+		},
+	}
+	// typeSuffixes are appended by the GORM dialectors to the column type,
+	// but are expressed as column attributes in Atlas.
+	typeSuffixes = []string{" PRIMARY KEY AUTOINCREMENT", " AUTO_INCREMENT", " NULL"}
+	// viewDefRe extracts the view definition from a CREATE [MATERIALIZED] VIEW statement.
+	viewDefRe = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?(?:MATERIALIZED\s+)?VIEW\s+.+?\s+AS\s+(.+?)(?:\s+WITH\s+(?:NO\s+)?DATA)?[\s;]*$`)
+)
+
+// marshalHCL converts the GORM schema of the given models into an Atlas HCL document.
+func (l *Loader) marshalHCL(m *migrator, tables []any, views []ViewDefiner, routines []any, objects []Object) (string, error) {
+	d, ok := hclDialects[l.dialect]
+	if !ok {
+		return "", fmt.Errorf("HCL output is not supported for dialect %q", l.dialect)
+	}
+	if len(routines) > 0 {
+		return "", errors.New("HCL output does not support functions and procedures")
+	}
+	if len(objects) > 0 {
+		return "", errors.New("HCL output does not support schema objects")
+	}
+	for _, model := range tables {
+		if md, ok := model.(interface {
+			Triggers(string) []Trigger
+		}); ok && len(md.Triggers(m.Dialector.Name())) > 0 {
+			return "", errors.New("HCL output does not support triggers")
+		}
+	}
+	if l.hclSchema != "" {
+		d.schema = l.hclSchema
+	}
+	if d.schema == "" {
+		return "", fmt.Errorf("HCL output requires a schema name for dialect %q", l.dialect)
+	}
+	if d.noViews && len(views) > 0 {
+		return "", fmt.Errorf("HCL output does not support views for dialect %q", l.dialect)
+	}
+	r, err := m.atlasRealm(d, tables, views)
+	if err != nil {
+		return "", err
+	}
+	var v any = r
+	if len(r.Schemas) == 1 {
+		v = r.Schemas[0]
+	}
+	b, err := d.marshaler.MarshalSpec(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// atlasRealm builds an Atlas realm from the GORM schema of the given models.
+// Tables and views with schema-qualified names are placed in their own schemas.
+func (m *migrator) atlasRealm(d hclDialect, tables []any, views []ViewDefiner) (*schema.Realm, error) {
+	var (
+		r       = schema.NewRealm(schema.New(d.schema))
+		schemas []*gschema.Schema
+	)
+	enums, err := m.DefineEnums(tables)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range enums {
+		r.Schemas[0].AddObjects(&schema.EnumType{T: e.name, Values: e.values, Schema: r.Schemas[0]})
+	}
+	for _, model := range m.ReorderModels(tables, true) {
+		err := m.RunWithValue(model, func(stmt *gorm.Statement) error {
+			// Join tables may be passed explicitly and also be added by ReorderModels.
+			if _, ok := realmTable(r, d, stmt.Schema.Table); ok {
+				return nil
+			}
+			t, err := m.atlasTable(d, stmt.Schema)
+			if err != nil {
+				return err
+			}
+			indexes, err := m.atlasIndexes(d, t.Column, stmt.Schema.Table, model)
+			if err != nil {
+				return err
+			}
+			t.AddIndexes(indexes...)
+			checks, err := m.modelChecks(model)
+			if err != nil {
+				return err
+			}
+			for _, c := range checks {
+				t.AddChecks(schema.NewCheck().SetName(c.name).SetExpr(c.expr))
+			}
+			realmSchema(r, d, stmt.Schema.Table).AddTables(t)
+			schemas = append(schemas, stmt.Schema)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !m.DB.DisableForeignKeyConstraintWhenMigrating && !m.DB.IgnoreRelationshipsWhenMigrating {
+		for _, gs := range schemas {
+			if err := m.atlasForeignKeys(r, d, gs); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, v := range views {
+		view, err := m.atlasView(d, v)
+		if err != nil {
+			return nil, err
+		}
+		realmSchema(r, d, m.resourceName(v)).AddViews(view)
+	}
+	return r, nil
+}
+
+// realmSchema returns the schema of the given, possibly qualified, name and creates it if it does not exist.
+func realmSchema(r *schema.Realm, d hclDialect, name string) *schema.Schema {
+	sn, _ := splitName(d, name)
+	s, ok := r.Schema(sn)
+	if !ok {
+		s = schema.New(sn)
+		r.AddSchemas(s)
+	}
+	return s
+}
+
+// realmTable returns the table of the given, possibly qualified, name.
+func realmTable(r *schema.Realm, d hclDialect, name string) (*schema.Table, bool) {
+	sn, tn := splitName(d, name)
+	s, ok := r.Schema(sn)
+	if !ok {
+		return nil, false
+	}
+	return s.Table(tn)
+}
+
+// splitName splits the given name into its schema and object names.
+func splitName(d hclDialect, name string) (string, string) {
+	if s, n, ok := strings.Cut(name, "."); ok {
+		return s, n
+	}
+	return d.schema, name
+}
+
+// atlasTable converts the given GORM schema into an Atlas table.
+func (m *migrator) atlasTable(d hclDialect, gs *gschema.Schema) (*schema.Table, error) {
+	_, name := splitName(d, gs.Table)
+	t := schema.NewTable(name)
+	for _, name := range gs.DBNames {
+		f := gs.FieldsByDBName[name]
+		if f.IgnoreMigration {
+			continue
+		}
+		c, err := m.atlasColumn(d, f)
+		if err != nil {
+			return nil, fmt.Errorf("converting column %s.%s: %w", gs.Table, name, err)
+		}
+		t.AddColumns(c)
+	}
+	if len(gs.PrimaryFields) > 0 {
+		pk := schema.NewPrimaryKey()
+		for _, f := range gs.PrimaryFields {
+			c, ok := t.Column(f.DBName)
+			if !ok {
+				return nil, fmt.Errorf("missing primary key column %s.%s", gs.Table, f.DBName)
+			}
+			pk.AddColumns(c)
+		}
+		t.SetPrimaryKey(pk)
+	}
+	for _, idx := range gs.ParseIndexes() {
+		i := schema.NewIndex(idx.Name).SetUnique(idx.Class == "UNIQUE")
+		for _, o := range idx.Fields {
+			p := &schema.IndexPart{Desc: strings.EqualFold(o.Sort, "DESC")}
+			switch c, ok := t.Column(o.DBName); {
+			case o.Expression != "":
+				p.X = &schema.RawExpr{X: o.Expression}
+			case ok:
+				p.C = c
+			default:
+				return nil, fmt.Errorf("missing index column %s.%s", gs.Table, o.DBName)
+			}
+			if o.Length > 0 && m.Dialector.Name() == "mysql" {
+				p.AddAttrs(&mysql.SubPart{Len: o.Length})
+			}
+			i.AddParts(p)
+		}
+		if idx.Where != "" && d.predicate != nil {
+			i.AddAttrs(d.predicate(idx.Where))
+		}
+		if idx.Comment != "" {
+			i.AddAttrs(&schema.Comment{Text: idx.Comment})
+		}
+		t.AddIndexes(i)
+	}
+	uniques := gs.ParseUniqueConstraints()
+	for _, name := range slices.Sorted(maps.Keys(uniques)) {
+		c, _ := t.Column(uniques[name].Field.DBName)
+		t.AddIndexes(schema.NewUniqueIndex(name).AddColumns(c))
+	}
+	checks := gs.ParseCheckConstraints()
+	for _, name := range slices.Sorted(maps.Keys(checks)) {
+		t.AddChecks(schema.NewCheck().SetName(name).SetExpr(checks[name].Constraint))
+	}
+	return t, nil
+}
+
+// atlasIndexes converts the indexes defined by the Indexes method of the given model,
+// resolving their columns using the given lookup function of its table or materialized view.
+func (m *migrator) atlasIndexes(d hclDialect, column func(string) (*schema.Column, bool), table string, model any) ([]*schema.Index, error) {
+	var indexes []*schema.Index
+	for _, idx := range m.modelIndexes(model) {
+		if _, err := m.indexStmt(table, idx); err != nil {
+			return nil, err
+		}
+		i := schema.NewIndex(idx.name).SetUnique(idx.unique)
+		for _, p := range idx.parts {
+			if p.expr != "" {
+				i.AddParts(&schema.IndexPart{X: &schema.RawExpr{X: p.expr}})
+				continue
+			}
+			c, ok := column(p.column)
+			if !ok {
+				return nil, fmt.Errorf("missing index column %s.%s", table, p.column)
+			}
+			i.AddColumns(c)
+		}
+		if idx.where != "" {
+			i.AddAttrs(d.predicate(idx.where))
+		}
+		if idx.method != "" {
+			i.AddAttrs(d.indexType(idx.method))
+		}
+		if len(idx.include) > 0 {
+			include := make([]*schema.Column, len(idx.include))
+			for j, name := range idx.include {
+				c, ok := column(name)
+				if !ok {
+					return nil, fmt.Errorf("missing index column %s.%s", table, name)
+				}
+				include[j] = c
+			}
+			i.AddAttrs(d.include(include))
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
+
+// atlasColumn converts the given GORM field into an Atlas column.
+func (m *migrator) atlasColumn(d hclDialect, f *gschema.Field) (*schema.Column, error) {
+	raw := m.columnType(f)
+	typ, err := d.parseType(raw)
+	if e, ok := fieldEnum(m.DB, f); ok && !e.integer && m.Dialector.Name() == "postgres" {
+		typ, err = &schema.EnumType{T: e.name, Values: e.values}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := schema.NewColumn(f.DBName).SetType(typ)
+	c.Type.Raw = raw
+	c.SetNull(!f.NotNull && !f.PrimaryKey)
+	if f.AutoIncrement && d.autoIncrement != nil {
+		c.AddAttrs(d.autoIncrement)
+	}
+	if x := m.defaultValue(f); x != nil {
+		c.SetDefault(x)
+	}
+	if f.Comment != "" {
+		c.SetComment(f.Comment)
+	}
+	return c, nil
+}
+
+// columnType returns the database type of the given field, as resolved by the GORM dialector.
+func (m *migrator) columnType(f *gschema.Field) string {
+	raw := m.DataTypeOf(f)
+	for _, s := range typeSuffixes {
+		raw = strings.TrimSuffix(raw, s)
+	}
+	return raw
+}
+
+// defaultValue returns the default value of the given field, or nil if it has none.
+func (m *migrator) defaultValue(f *gschema.Field) schema.Expr {
+	switch {
+	case !f.HasDefaultValue:
+	case f.DefaultValueInterface != nil:
+		stmt := &gorm.Statement{Vars: []any{f.DefaultValueInterface}}
+		m.Dialector.BindVarTo(stmt, stmt, f.DefaultValueInterface)
+		return &schema.Literal{V: m.Dialector.Explain(stmt.SQL.String(), f.DefaultValueInterface)}
+	case f.DefaultValue != "" && f.DefaultValue != "(-)":
+		return &schema.RawExpr{X: f.DefaultValue}
+	}
+	return nil
+}
+
+// atlasForeignKeys adds the foreign keys owned by the given GORM schema to its table.
+func (m *migrator) atlasForeignKeys(r *schema.Realm, d hclDialect, gs *gschema.Schema) error {
+	t, ok := realmTable(r, d, gs.Table)
+	if !ok {
+		return fmt.Errorf("missing table %s", gs.Table)
+	}
+	for _, name := range slices.Sorted(maps.Keys(gs.Relationships.Relations)) {
+		rel := gs.Relationships.Relations[name]
+		if rel.Field.IgnoreMigration {
+			continue
+		}
+		c := rel.ParseConstraint()
+		if c == nil || c.Schema != gs {
+			continue
+		}
+		ref, ok := realmTable(r, d, c.ReferenceSchema.Table)
+		if !ok {
+			return fmt.Errorf("table %s referenced by %s.%s is not loaded", c.ReferenceSchema.Table, gs.Table, c.Name)
+		}
+		fk := schema.NewForeignKey(c.Name).SetTable(t).SetRefTable(ref).
+			SetOnDelete(schema.ReferenceOption(strings.ToUpper(c.OnDelete))).
+			SetOnUpdate(schema.ReferenceOption(strings.ToUpper(c.OnUpdate)))
+		for _, f := range c.ForeignKeys {
+			col, ok := t.Column(f.DBName)
+			if !ok {
+				return fmt.Errorf("missing foreign key column %s.%s", gs.Table, f.DBName)
+			}
+			fk.AddColumns(col)
+		}
+		for _, f := range c.References {
+			col, ok := ref.Column(f.DBName)
+			if !ok {
+				return fmt.Errorf("missing referenced column %s.%s", ref.Name, f.DBName)
+			}
+			fk.AddRefColumns(col)
+		}
+		t.AddForeignKeys(fk)
+	}
+	return nil
+}
+
+// atlasView converts the given view-based model into an Atlas view.
+func (m *migrator) atlasView(d hclDialect, v ViewDefiner) (*schema.View, error) {
+	b, err := m.buildView(v)
+	if err != nil {
+		return nil, err
+	}
+	def := b.viewDef
+	if def == "" {
+		matches := viewDefRe.FindStringSubmatch(b.createStmt)
+		if matches == nil {
+			return nil, fmt.Errorf("unexpected definition for view %s: %q", b.viewName, b.createStmt)
+		}
+		def = matches[1]
+	}
+	_, name := splitName(d, b.viewName)
+	view := schema.NewView(name, def)
+	if b.materialized {
+		view = schema.NewMaterializedView(name, def)
+	}
+	err = m.RunWithValue(v, func(stmt *gorm.Statement) error {
+		for _, name := range stmt.Schema.DBNames {
+			// Views have no keys, therefore their columns are neither auto-incremented nor implicitly NOT NULL.
+			f := *stmt.Schema.FieldsByDBName[name]
+			f.PrimaryKey, f.AutoIncrement = false, false
+			c, err := m.atlasColumn(d, &f)
+			if err != nil {
+				return fmt.Errorf("converting column %s.%s: %w", b.viewName, name, err)
+			}
+			view.AddColumns(c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := m.atlasIndexes(d, view.Column, b.viewName, v)
+	if err != nil {
+		return nil, err
+	}
+	view.AddIndexes(indexes...)
+	return view, nil
+}
@@ -0,0 +1,190 @@
+package gormschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// Index defines an index that cannot be expressed using GORM struct tags,
+	// such as partial and expression indexes.
+	Index struct {
+		name    string
+		parts   []indexPart
+		unique  bool
+		where   string
+		include []string
+		method  string
+	}
+	// IndexOption configures an Index.
+	IndexOption func(*Index)
+	// indexPart is either a column or an expression of an index.
+	indexPart struct {
+		column string
+		expr   string
+	}
+)
+
+// NewIndex receives the index name and a list of IndexOption to build an Index.
+func NewIndex(name string, opts ...IndexOption) Index {
+	idx := Index{name: name}
+	for _, opt := range opts {
+		opt(&idx)
+	}
+	return idx
+}
+
+// IndexColumns appends the given columns to the index.
+func IndexColumns(columns ...string) IndexOption {
+	return func(idx *Index) {
+		for _, c := range columns {
+			idx.parts = append(idx.parts, indexPart{column: c})
+		}
+	}
+}
+
+// IndexExpression appends the given expression to the index. For example, "lower(email)".
+// It is supported by MySQL, PostgreSQL and SQLite.
+func IndexExpression(expr string) IndexOption {
+	return func(idx *Index) {
+		idx.parts = append(idx.parts, indexPart{expr: expr})
+	}
+}
+
+// IndexPredicate makes the index partial. For example, "deleted_at IS NULL".
+// It is supported by PostgreSQL, SQLite and SQL Server.
+func IndexPredicate(predicate string) IndexOption {
+	return func(idx *Index) {
+		idx.where = predicate
+	}
+}
+
+// IndexInclude adds the given non-key columns to the index. It is supported by PostgreSQL
+// and SQL Server, and is translated to the STORING clause on Spanner.
+func IndexInclude(columns ...string) IndexOption {
+	return func(idx *Index) {
+		idx.include = append(idx.include, columns...)
+	}
+}
+
+// IndexMethod sets the index method. For example, "GIN" on PostgreSQL, "BTREE" on MySQL,
+// or "NONCLUSTERED" on SQL Server. It is not supported by SQLite and Spanner.
+func IndexMethod(method string) IndexOption {
+	return func(idx *Index) {
+		idx.method = method
+	}
+}
+
+// IndexUnique makes the index unique.
+func IndexUnique() IndexOption {
+	return func(idx *Index) {
+		idx.unique = true
+	}
+}
+
+// modelIndexes returns the indexes defined by the Indexes method of the given model, if any.
+func (m *migrator) modelIndexes(model any) []Index {
+	if md, ok := model.(interface {
+		Indexes(string) []Index
+	}); ok {
+		return md.Indexes(m.Dialector.Name())
+	}
+	return nil
+}
+
+// CreateIndexes creates the indexes defined by the Indexes method of the given models.
+func (m *migrator) CreateIndexes(models []any) error {
+	for _, model := range models {
+		indexes := m.modelIndexes(model)
+		if len(indexes) == 0 {
+			continue
+		}
+		err := m.RunWithValue(model, func(stmt *gorm.Statement) error {
+			for _, idx := range indexes {
+				s, err := m.indexStmt(stmt.Schema.Table, idx)
+				if err != nil {
+					return err
+				}
+				if err := m.DB.Exec(s).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexStmt returns the CREATE INDEX statement of the given index.
+func (m *migrator) indexStmt(table string, idx Index) (string, error) {
+	var (
+		dialect = m.Dialector.Name()
+		q       = m.DB.Statement.Quote
+		b       strings.Builder
+	)
+	unsupported := func(feature string) error {
+		return fmt.Errorf("index %q on %s: %s are not supported by %s", idx.name, table, feature, dialect)
+	}
+	if len(idx.parts) == 0 {
+		return "", fmt.Errorf("index %q on %s: missing columns", idx.name, table)
+	}
+	// ClickHouse data skipping indexes are defined by the table engine
+	// and cannot be created with a CREATE INDEX statement.
+	if dialect == "clickhouse" {
+		return "", unsupported("indexes")
+	}
+	parts := make([]string, len(idx.parts))
+	for i, p := range idx.parts {
+		switch {
+		case p.column != "":
+			parts[i] = q(p.column)
+		case dialect == "sqlserver" || dialect == "spanner":
+			return "", unsupported("expressions")
+		default:
+			parts[i] = "(" + p.expr + ")"
+		}
+	}
+	switch {
+	case idx.where != "" && (dialect == "mysql" || dialect == "spanner"):
+		return "", unsupported("predicates")
+	case len(idx.include) > 0 && (dialect == "mysql" || dialect == "sqlite"):
+		return "", unsupported("included columns")
+	case idx.method != "" && (dialect == "sqlite" || dialect == "spanner"):
+		return "", unsupported("index methods")
+	}
+	b.WriteString("CREATE ")
+	if idx.unique {
+		b.WriteString("UNIQUE ")
+	}
+	if dialect == "sqlserver" && idx.method != "" {
+		b.WriteString(idx.method + " ")
+	}
+	fmt.Fprintf(&b, "INDEX %s ON %s ", q(idx.name), q(table))
+	if dialect == "postgres" && idx.method != "" {
+		fmt.Fprintf(&b, "USING %s ", idx.method)
+	}
+	fmt.Fprintf(&b, "(%s)", strings.Join(parts, ","))
+	if dialect == "mysql" && idx.method != "" {
+		fmt.Fprintf(&b, " USING %s", idx.method)
+	}
+	if len(idx.include) > 0 {
+		include := make([]string, len(idx.include))
+		for i, c := range idx.include {
+			include[i] = q(c)
+		}
+		clause := "INCLUDE"
+		if dialect == "spanner" {
+			clause = "STORING"
+		}
+		fmt.Fprintf(&b, " %s (%s)", clause, strings.Join(include, ","))
+	}
+	if idx.where != "" {
+		fmt.Fprintf(&b, " WHERE %s", idx.where)
+	}
+	return b.String(), nil
+}
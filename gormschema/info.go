@@ -0,0 +1,380 @@
+package gormschema
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+	"gorm.io/gorm"
+	gschema "gorm.io/gorm/schema"
+)
+
+type (
+	// SchemaInfo describes the schema objects resolved by GORM for the loaded models.
+	SchemaInfo struct {
+		Objects   []*ObjectInfo   `json:"objects,omitempty"`
+		Enums     []*EnumInfo     `json:"enums,omitempty"`
+		Tables    []*TableInfo    `json:"tables,omitempty"`
+		Views     []*ViewInfo     `json:"views,omitempty"`
+		Functions []*FunctionInfo `json:"functions,omitempty"`
+		Triggers  []*TriggerInfo  `json:"triggers,omitempty"`
+	}
+	// ObjectInfo describes a schema object defined for the loaded dialect.
+	ObjectInfo struct {
+		Kind ObjectKind `json:"kind"`
+		Name string     `json:"name"`
+		// DependsOn holds the names of the objects the object depends on.
+		DependsOn []string `json:"depends_on,omitempty"`
+		Stmt      string   `json:"stmt"`
+	}
+	// EnumInfo describes an enum type created for an EnumDefiner.
+	EnumInfo struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}
+	// TableInfo describes a table created for a model or a join table.
+	TableInfo struct {
+		// Schema is the schema of the table, if its name is schema-qualified.
+		Schema string `json:"schema,omitempty"`
+		Name   string `json:"name"`
+		// Model is the Go type of the model. It is empty for join tables created implicitly by GORM.
+		Model       string            `json:"model,omitempty"`
+		Pos         string            `json:"pos,omitempty"`
+		Columns     []*ColumnInfo     `json:"columns"`
+		PrimaryKey  []string          `json:"primary_key,omitempty"`
+		Indexes     []*IndexInfo      `json:"indexes,omitempty"`
+		Constraints []*ConstraintInfo `json:"constraints,omitempty"`
+	}
+	// ColumnInfo describes a column of a table or a view.
+	ColumnInfo struct {
+		// Field is the name of the Go struct field.
+		Field         string `json:"field"`
+		Name          string `json:"name"`
+		Type          string `json:"type"`
+		Nullable      bool   `json:"nullable"`
+		Default       string `json:"default,omitempty"`
+		PrimaryKey    bool   `json:"primary_key,omitempty"`
+		AutoIncrement bool   `json:"auto_increment,omitempty"`
+		Comment       string `json:"comment,omitempty"`
+	}
+	// IndexInfo describes an index of a table or a materialized view.
+	IndexInfo struct {
+		Name    string   `json:"name"`
+		Unique  bool     `json:"unique,omitempty"`
+		Columns []string `json:"columns"`
+		// Include holds the non-key columns of the index.
+		Include []string `json:"include,omitempty"`
+		// Class holds the index class, such as FULLTEXT or SPATIAL.
+		Class   string `json:"class,omitempty"`
+		Type    string `json:"type,omitempty"`
+		Where   string `json:"where,omitempty"`
+		Comment string `json:"comment,omitempty"`
+	}
+	// ConstraintInfo describes a FOREIGN KEY, UNIQUE or CHECK constraint of a table.
+	ConstraintInfo struct {
+		Name       string   `json:"name"`
+		Type       string   `json:"type"`
+		Columns    []string `json:"columns,omitempty"`
+		RefTable   string   `json:"ref_table,omitempty"`
+		RefColumns []string `json:"ref_columns,omitempty"`
+		OnDelete   string   `json:"on_delete,omitempty"`
+		OnUpdate   string   `json:"on_update,omitempty"`
+		Expr       string   `json:"expr,omitempty"`
+	}
+	// ViewInfo describes a view created for a view-based model.
+	ViewInfo struct {
+		Schema       string        `json:"schema,omitempty"`
+		Name         string        `json:"name"`
+		Model        string        `json:"model"`
+		Materialized bool          `json:"materialized,omitempty"`
+		Pos          string        `json:"pos,omitempty"`
+		Columns      []*ColumnInfo `json:"columns"`
+		Indexes      []*IndexInfo  `json:"indexes,omitempty"`
+		Stmt         string        `json:"stmt"`
+	}
+	// FunctionInfo describes a function or procedure defined by a model.
+	FunctionInfo struct {
+		Name string `json:"name"`
+		// Type is either "function" or "procedure".
+		Type  string `json:"type"`
+		Model string `json:"model"`
+		Pos   string `json:"pos,omitempty"`
+		Stmt  string `json:"stmt"`
+	}
+	// TriggerInfo describes a trigger defined by a model.
+	TriggerInfo struct {
+		Name string `json:"name,omitempty"`
+		// Table is the (possibly schema-qualified) table or view the model is mapped to.
+		Table string `json:"table"`
+		Model string `json:"model"`
+		Stmt  string `json:"stmt"`
+	}
+)
+
+// Constraint types reported by ConstraintInfo.
+const (
+	ConstraintForeignKey = "FOREIGN KEY"
+	ConstraintUnique     = "UNIQUE"
+	ConstraintCheck      = "CHECK"
+)
+
+// LoadSchema loads the models and returns a structured description of the schema
+// objects GORM resolves for them, without generating any DDL.
+func (l *Loader) LoadSchema(models ...any) (*SchemaInfo, error) {
+	tables, _, _, _ := splitModels(models)
+	db, cm, err := l.open()
+	if err != nil {
+		return nil, err
+	}
+	defer cm.close(db)
+	cm.models = models
+	if err = cm.setupJoinTables(tables...); err != nil {
+		return nil, err
+	}
+	return l.schemaInfo(cm, models)
+}
+
+// marshalJSON returns the JSON-encoded SchemaInfo of the given models.
+func (l *Loader) marshalJSON(cm *migrator, models []any) (string, error) {
+	info, err := l.schemaInfo(cm, models)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+func (l *Loader) schemaInfo(cm *migrator, models []any) (*SchemaInfo, error) {
+	var (
+		info                             = &SchemaInfo{}
+		tables, views, routines, objects = splitModels(models)
+		pos                              = make(map[string]string, len(l.modelPos))
+	)
+	enums, err := cm.DefineEnums(tables)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range enums {
+		info.Enums = append(info.Enums, &EnumInfo{Name: e.name, Values: e.values})
+	}
+	if objects, err = orderObjects(objects, cm.Dialector.Name()); err != nil {
+		return nil, err
+	}
+	for _, o := range objects {
+		info.Objects = append(info.Objects, &ObjectInfo{Kind: o.kind, Name: o.name, DependsOn: o.deps, Stmt: o.stmts[cm.Dialector.Name()]})
+	}
+	for m, p := range l.modelPos {
+		switch m.(type) {
+		case FunctionDefiner, ProcedureDefiner:
+			pos[routineType(m)+":"+cm.routineName(m)] = p
+		default:
+			pos[cm.resourceName(m)] = p
+		}
+	}
+	for _, model := range cm.ReorderModels(tables, true) {
+		err := cm.RunWithValue(model, func(stmt *gorm.Statement) error {
+			// Join tables may be passed explicitly and also be added by ReorderModels.
+			if slices.ContainsFunc(info.Tables, func(t *TableInfo) bool { return qualifiedName(t.Schema, t.Name) == stmt.Schema.Table }) {
+				return nil
+			}
+			t := cm.tableInfo(stmt.Schema)
+			t.Pos = pos[stmt.Schema.Table]
+			for _, idx := range cm.modelIndexes(model) {
+				if _, err := cm.indexStmt(stmt.Schema.Table, idx); err != nil {
+					return err
+				}
+				t.Indexes = append(t.Indexes, idx.info())
+			}
+			checks, err := cm.modelChecks(model)
+			if err != nil {
+				return err
+			}
+			for _, c := range checks {
+				t.Constraints = append(t.Constraints, &ConstraintInfo{Name: c.name, Type: ConstraintCheck, Expr: c.expr})
+			}
+			info.Tables = append(info.Tables, t)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range views {
+		b, err := cm.buildView(v)
+		if err != nil {
+			return nil, err
+		}
+		view := &ViewInfo{Name: b.viewName, Model: modelName(v), Materialized: b.materialized, Pos: pos[b.viewName], Stmt: b.createStmt}
+		if sn, n, ok := strings.Cut(b.viewName, "."); ok {
+			view.Schema, view.Name = sn, n
+		}
+		for _, idx := range cm.modelIndexes(v) {
+			if _, err := cm.indexStmt(b.viewName, idx); err != nil {
+				return nil, err
+			}
+			view.Indexes = append(view.Indexes, idx.info())
+		}
+		err = cm.RunWithValue(v, func(stmt *gorm.Statement) error {
+			for _, name := range stmt.Schema.DBNames {
+				f := *stmt.Schema.FieldsByDBName[name]
+				f.PrimaryKey, f.AutoIncrement = false, false
+				view.Columns = append(view.Columns, cm.columnInfo(&f))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		info.Views = append(info.Views, view)
+	}
+	for _, r := range routines {
+		b := cm.buildRoutine(r)
+		if b.createStmt == "" {
+			continue
+		}
+		f := &FunctionInfo{Name: cm.routineName(r), Type: routineType(r), Model: modelName(r), Stmt: b.createStmt}
+		f.Pos = pos[f.Type+":"+f.Name]
+		info.Functions = append(info.Functions, f)
+	}
+	for _, model := range models {
+		md, ok := model.(interface {
+			Triggers(string) []Trigger
+		})
+		if !ok {
+			continue
+		}
+		for _, t := range md.Triggers(cm.Dialector.Name()) {
+			b, _, err := cm.triggerBuilder(model, t)
+			if err != nil {
+				return nil, err
+			}
+			stmts, err := b.triggerStmts()
+			if err != nil {
+				return nil, err
+			}
+			if len(stmts) == 0 {
+				continue
+			}
+			info.Triggers = append(info.Triggers, &TriggerInfo{Name: b.triggerName(), Table: cm.resourceName(model), Model: modelName(model), Stmt: strings.Join(stmts, ";\n")})
+		}
+	}
+	return info, nil
+}
+
+// tableInfo describes the table of the given GORM schema.
+func (m *migrator) tableInfo(gs *gschema.Schema) *TableInfo {
+	t := &TableInfo{Name: gs.Table}
+	if s, n, ok := strings.Cut(gs.Table, "."); ok {
+		t.Schema, t.Name = s, n
+	}
+	// Join tables created by GORM are unnamed struct types.
+	if gs.ModelType.Name() != "" {
+		t.Model = gs.ModelType.String()
+	}
+	for _, name := range gs.DBNames {
+		if f := gs.FieldsByDBName[name]; !f.IgnoreMigration {
+			t.Columns = append(t.Columns, m.columnInfo(f))
+		}
+	}
+	for _, f := range gs.PrimaryFields {
+		t.PrimaryKey = append(t.PrimaryKey, f.DBName)
+	}
+	for _, idx := range gs.ParseIndexes() {
+		i := &IndexInfo{Name: idx.Name, Unique: idx.Class == "UNIQUE", Type: idx.Type, Where: idx.Where, Comment: idx.Comment}
+		if !i.Unique {
+			i.Class = idx.Class
+		}
+		for _, o := range idx.Fields {
+			if o.Expression != "" {
+				i.Columns = append(i.Columns, o.Expression)
+			} else {
+				i.Columns = append(i.Columns, o.DBName)
+			}
+		}
+		t.Indexes = append(t.Indexes, i)
+	}
+	if !m.DB.DisableForeignKeyConstraintWhenMigrating && !m.DB.IgnoreRelationshipsWhenMigrating {
+		for _, name := range slices.Sorted(maps.Keys(gs.Relationships.Relations)) {
+			rel := gs.Relationships.Relations[name]
+			if rel.Field.IgnoreMigration {
+				continue
+			}
+			c := rel.ParseConstraint()
+			if c == nil || c.Schema != gs {
+				continue
+			}
+			fk := &ConstraintInfo{
+				Name:     c.Name,
+				Type:     ConstraintForeignKey,
+				RefTable: c.ReferenceSchema.Table,
+				OnDelete: strings.ToUpper(c.OnDelete),
+				OnUpdate: strings.ToUpper(c.OnUpdate),
+			}
+			for _, f := range c.ForeignKeys {
+				fk.Columns = append(fk.Columns, f.DBName)
+			}
+			for _, f := range c.References {
+				fk.RefColumns = append(fk.RefColumns, f.DBName)
+			}
+			t.Constraints = append(t.Constraints, fk)
+		}
+	}
+	uniques := gs.ParseUniqueConstraints()
+	for _, name := range slices.Sorted(maps.Keys(uniques)) {
+		t.Constraints = append(t.Constraints, &ConstraintInfo{Name: name, Type: ConstraintUnique, Columns: []string{uniques[name].Field.DBName}})
+	}
+	checks := gs.ParseCheckConstraints()
+	for _, name := range slices.Sorted(maps.Keys(checks)) {
+		t.Constraints = append(t.Constraints, &ConstraintInfo{Name: name, Type: ConstraintCheck, Expr: checks[name].Constraint})
+	}
+	return t
+}
+
+// info describes the index.
+func (idx Index) info() *IndexInfo {
+	i := &IndexInfo{Name: idx.name, Unique: idx.unique, Include: idx.include, Type: idx.method, Where: idx.where}
+	for _, p := range idx.parts {
+		i.Columns = append(i.Columns, cmp.Or(p.column, p.expr))
+	}
+	return i
+}
+
+// columnInfo describes the column of the given GORM field.
+func (m *migrator) columnInfo(f *gschema.Field) *ColumnInfo {
+	c := &ColumnInfo{
+		Field:         f.Name,
+		Name:          f.DBName,
+		Type:          m.columnType(f),
+		Nullable:      !f.NotNull && !f.PrimaryKey,
+		PrimaryKey:    f.PrimaryKey,
+		AutoIncrement: f.AutoIncrement,
+		Comment:       f.Comment,
+	}
+	switch x := m.defaultValue(f).(type) {
+	case *schema.Literal:
+		c.Default = x.V
+	case *schema.RawExpr:
+		c.Default = x.X
+	}
+	return c
+}
+
+// qualifiedName returns the name of an object, qualified with its schema if it is set.
+func qualifiedName(s, name string) string {
+	if s == "" {
+		return name
+	}
+	return s + "." + name
+}
+
+// modelName returns the Go type name of the given model.
+func modelName(model any) string {
+	return indirect(reflect.TypeOf(model)).String()
+}
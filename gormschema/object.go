@@ -0,0 +1,85 @@
+package gormschema
+
+import (
+	"fmt"
+	"slices"
+)
+
+type (
+	// ObjectKind is the kind of a schema object, such as an extension or a sequence.
+	ObjectKind string
+	// Object defines a schema object that is not mapped to a model, such as a PostgreSQL
+	// extension, sequence or domain. Objects are passed to Load alongside the models, and
+	// are created before the tables.
+	Object struct {
+		kind  ObjectKind
+		name  string
+		stmts map[string]string
+		deps  []string
+	}
+	// ObjectOption configures an Object.
+	ObjectOption func(*Object)
+)
+
+// Object kinds. Other kinds may be used to describe objects that are not listed here.
+const (
+	ObjectExtension ObjectKind = "extension"
+	ObjectSequence  ObjectKind = "sequence"
+	ObjectDomain    ObjectKind = "domain"
+)
+
+// NewObject receives the kind and name of a schema object and a list of ObjectOption to build an Object.
+// For example:
+//
+//	gormschema.NewObject(gormschema.ObjectExtension, "citext",
+//		gormschema.ObjectStmt("postgres", "CREATE EXTENSION IF NOT EXISTS citext"),
+//	)
+func NewObject(kind ObjectKind, name string, opts ...ObjectOption) Object {
+	o := Object{kind: kind, name: name, stmts: make(map[string]string)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// ObjectStmt sets the statement that creates the object on the given dialect.
+// Objects without a statement for the loaded dialect are skipped.
+func ObjectStmt(dialect, stmt string) ObjectOption {
+	return func(o *Object) {
+		o.stmts[dialect] = stmt
+	}
+}
+
+// ObjectDependsOn declares the objects the object depends on, by their names.
+// An object is created after its dependencies.
+func ObjectDependsOn(names ...string) ObjectOption {
+	return func(o *Object) {
+		o.deps = append(o.deps, names...)
+	}
+}
+
+// orderObjects returns the objects defined for the given dialect, ordered by their dependencies.
+func orderObjects(objects []Object, dialect string) ([]Object, error) {
+	var defined []Object
+	for _, o := range objects {
+		for _, d := range o.deps {
+			if !slices.ContainsFunc(objects, func(x Object) bool { return x.name == d }) {
+				return nil, fmt.Errorf("%s %s: missing dependency %s", o.kind, o.name, d)
+			}
+		}
+		if o.stmts[dialect] != "" {
+			defined = append(defined, o)
+		}
+	}
+	return topoSort(defined, func(o Object) string { return o.name }, func(o Object) []string { return o.deps })
+}
+
+// CreateObjects creates the given objects, which are expected to be ordered by their dependencies.
+func (m *migrator) CreateObjects(objects []Object) error {
+	for _, o := range objects {
+		if err := m.DB.Exec(o.stmts[m.Dialector.Name()]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+CREATE TABLE `events`(`user_id` UInt64,`name` LowCardinality(String),`created_at` DateTime64(3)  ) ENGINE=MergeTree() ORDER BY (user_id, created_at) PARTITION BY toYYYYMM(created_at) TTL created_at + INTERVAL 1 YEAR;
+CREATE TABLE `page_views`(`id` UInt64,`path` String,`version` UInt64,`created_at` DateTime64(3)  ) ENGINE=ReplacingMergeTree(version) ORDER BY id;
+CREATE TABLE `sessions`(`id` UUID,`started_at` DateTime64(3)  ) ENGINE=MergeTree() ORDER BY tuple();
@@ -0,0 +1,3 @@
+CREATE TABLE `products` (`id` bigint unsigned AUTO_INCREMENT,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,`deleted_at` datetime(3) NULL,`sku` varchar(8),`price` bigint,`discount_price` bigint,PRIMARY KEY (`id`),INDEX `idx_products_deleted_at` (`deleted_at`));
+ALTER TABLE `products` ADD CONSTRAINT `chk_products_discount` CHECK (discount_price >= 0 AND discount_price < price);
+ALTER TABLE `products` ADD CONSTRAINT `chk_products_sku` CHECK (char_length(sku) = 8);
@@ -0,0 +1,141 @@
+table "events" {
+  schema = schema.app
+  column "eventId" {
+    null = false
+    type = varchar(191)
+  }
+  column "locationId" {
+    null = true
+    type = varchar(191)
+  }
+  primary_key {
+    columns = [column.eventId]
+  }
+  foreign_key "fk_locations_event" {
+    columns     = [column.locationId]
+    ref_columns = [table.locations.column.locationId]
+  }
+  index "idx_events_location_id" {
+    unique  = true
+    columns = [column.locationId]
+  }
+}
+table "locations" {
+  schema = schema.app
+  column "locationId" {
+    null = false
+    type = varchar(191)
+  }
+  column "eventId" {
+    null = true
+    type = varchar(191)
+  }
+  primary_key {
+    columns = [column.locationId]
+  }
+  foreign_key "fk_events_location" {
+    columns     = [column.eventId]
+    ref_columns = [table.events.column.eventId]
+  }
+  index "idx_locations_event_id" {
+    unique  = true
+    columns = [column.eventId]
+  }
+}
+table "user_pet_histories" {
+  schema = schema.app
+  column "user_id" {
+    null     = false
+    type     = bigint
+    unsigned = true
+  }
+  column "pet_id" {
+    null     = false
+    type     = bigint
+    unsigned = true
+  }
+  column "created_at" {
+    null = true
+    type = datetime(3)
+  }
+  primary_key {
+    columns = [column.user_id, column.pet_id]
+  }
+}
+table "users" {
+  schema = schema.app
+  column "id" {
+    null           = false
+    type           = bigint
+    unsigned       = true
+    auto_increment = true
+  }
+  column "created_at" {
+    null = true
+    type = datetime(3)
+  }
+  column "updated_at" {
+    null = true
+    type = datetime(3)
+  }
+  column "deleted_at" {
+    null = true
+    type = datetime(3)
+  }
+  column "name" {
+    null = true
+    type = longtext
+  }
+  column "age" {
+    null = true
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_users_deleted_at" {
+    columns = [column.deleted_at]
+  }
+}
+table "hobbies" {
+  schema = schema.app
+  column "id" {
+    null           = false
+    type           = bigint
+    unsigned       = true
+    auto_increment = true
+  }
+  column "name" {
+    null = true
+    type = longtext
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+table "user_hobbies" {
+  schema = schema.app
+  column "hobby_id" {
+    null     = false
+    type     = bigint
+    unsigned = true
+  }
+  column "user_id" {
+    null     = false
+    type     = bigint
+    unsigned = true
+  }
+  primary_key {
+    columns = [column.hobby_id, column.user_id]
+  }
+  foreign_key "fk_user_hobbies_hobby" {
+    columns     = [column.hobby_id]
+    ref_columns = [table.hobbies.column.id]
+  }
+  foreign_key "fk_user_hobbies_user" {
+    columns     = [column.user_id]
+    ref_columns = [table.users.column.id]
+  }
+}
+schema "app" {
+}
@@ -0,0 +1,2 @@
+CREATE TABLE `tickets` (`id` bigint unsigned AUTO_INCREMENT,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,`deleted_at` datetime(3) NULL,`title` longtext,`status` ENUM('open', 'pending', 'closed') NOT NULL DEFAULT 'open',`previous_status` ENUM('open', 'pending', 'closed'),`priority` bigint,PRIMARY KEY (`id`),INDEX `idx_tickets_deleted_at` (`deleted_at`));
+ALTER TABLE `tickets` ADD CONSTRAINT `chk_tickets_priority` CHECK (`priority` IN (1, 2, 3));
@@ -0,0 +1,9 @@
+-- atlas:pos archive_pets[type=procedure] /internal/testdata/functions/models.go:61
+-- atlas:pos pet_count[type=function] /internal/testdata/functions/models.go:39
+-- atlas:pos pets[type=table] /internal/testdata/functions/models.go:11
+
+CREATE TABLE `pets` (`id` bigint unsigned AUTO_INCREMENT,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,`deleted_at` datetime(3) NULL,`name` longtext,`user_id` bigint unsigned,PRIMARY KEY (`id`),INDEX `idx_pets_deleted_at` (`deleted_at`));
+CREATE TABLE `pet_histories` (`user_id` bigint unsigned,`pet_id` bigint unsigned,`created_at` datetime(3) NULL,PRIMARY KEY (`user_id`,`pet_id`));
+CREATE FUNCTION pet_count(uid BIGINT UNSIGNED) RETURNS BIGINT
+READS SQL DATA
+RETURN (SELECT COUNT(*) FROM pets WHERE user_id = uid);
@@ -0,0 +1,3 @@
+CREATE TABLE `customers` (`id` bigint unsigned AUTO_INCREMENT,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,`deleted_at` datetime(3) NULL,`email` varchar(255),`name` varchar(255),`country` varchar(2),PRIMARY KEY (`id`),INDEX `idx_customers_deleted_at` (`deleted_at`));
+CREATE UNIQUE INDEX `idx_customers_email` ON `customers` ((lower(email)));
+CREATE INDEX `idx_customers_country` ON `customers` (`country`,`name`) USING BTREE;
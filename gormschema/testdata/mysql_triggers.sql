@@ -0,0 +1,14 @@
+CREATE TABLE `pets` (`id` bigint unsigned AUTO_INCREMENT,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,`deleted_at` datetime(3) NULL,`name` longtext,`user_id` bigint unsigned,PRIMARY KEY (`id`),INDEX `idx_pets_deleted_at` (`deleted_at`));
+CREATE TABLE `pet_histories` (`user_id` bigint unsigned,`pet_id` bigint unsigned,`created_at` datetime(3) NULL,PRIMARY KEY (`user_id`,`pet_id`));
+CREATE TRIGGER `trg_insert_pet_history`
+AFTER INSERT ON `pets`
+FOR EACH ROW
+BEGIN
+	INSERT INTO pet_histories (user_id, pet_id, created_at) VALUES (NEW.user_id, NEW.id, NOW(3));
+END;
+CREATE TRIGGER `trg_adding_heart_on_pet`
+BEFORE INSERT ON `pets`
+FOR EACH ROW
+BEGIN
+	SET NEW.name = CONCAT(NEW.name, ' <3');
+END;
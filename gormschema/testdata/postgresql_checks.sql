@@ -0,0 +1,4 @@
+CREATE TABLE "products" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"sku" varchar(8),"price" bigint,"discount_price" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_products_deleted_at" ON "products" ("deleted_at");
+ALTER TABLE "products" ADD CONSTRAINT "chk_products_discount" CHECK (discount_price >= 0 AND discount_price < price);
+ALTER TABLE "products" ADD CONSTRAINT "chk_products_sku" CHECK (char_length(sku) = 8);
@@ -0,0 +1,157 @@
+table "events" {
+  schema = schema.public
+  column "eventId" {
+    null = false
+    type = varchar(191)
+  }
+  column "locationId" {
+    null = true
+    type = varchar(191)
+  }
+  primary_key {
+    columns = [column.eventId]
+  }
+  foreign_key "fk_locations_event" {
+    columns     = [column.locationId]
+    ref_columns = [table.locations.column.locationId]
+  }
+  index "idx_events_location_id" {
+    unique  = true
+    columns = [column.locationId]
+  }
+}
+table "locations" {
+  schema = schema.public
+  column "locationId" {
+    null = false
+    type = varchar(191)
+  }
+  column "eventId" {
+    null = true
+    type = varchar(191)
+  }
+  primary_key {
+    columns = [column.locationId]
+  }
+  foreign_key "fk_events_location" {
+    columns     = [column.eventId]
+    ref_columns = [table.events.column.eventId]
+  }
+  index "idx_locations_event_id" {
+    unique  = true
+    columns = [column.eventId]
+  }
+}
+table "user_pet_histories" {
+  schema = schema.public
+  column "user_id" {
+    null = false
+    type = bigint
+  }
+  column "pet_id" {
+    null = false
+    type = bigint
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  primary_key {
+    columns = [column.user_id, column.pet_id]
+  }
+}
+table "users" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  column "updated_at" {
+    null = true
+    type = timestamptz
+  }
+  column "deleted_at" {
+    null = true
+    type = timestamptz
+  }
+  column "name" {
+    null = true
+    type = text
+  }
+  column "age" {
+    null = true
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_users_deleted_at" {
+    columns = [column.deleted_at]
+  }
+}
+table "hobbies" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "name" {
+    null = true
+    type = text
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+table "user_hobbies" {
+  schema = schema.public
+  column "hobby_id" {
+    null = false
+    type = bigint
+  }
+  column "user_id" {
+    null = false
+    type = bigint
+  }
+  primary_key {
+    columns = [column.hobby_id, column.user_id]
+  }
+  foreign_key "fk_user_hobbies_hobby" {
+    columns     = [column.hobby_id]
+    ref_columns = [table.hobbies.column.id]
+  }
+  foreign_key "fk_user_hobbies_user" {
+    columns     = [column.user_id]
+    ref_columns = [table.users.column.id]
+  }
+}
+view "working_aged_users" {
+  schema = schema.public
+  column "name" {
+    null = true
+    type = text
+  }
+  column "age" {
+    null = true
+    type = bigint
+  }
+  as = "SELECT name, age FROM \"users\" WHERE age BETWEEN 18 AND 65"
+}
+view "top_pet_owners" {
+  schema = schema.public
+  column "name" {
+    null = true
+    type = text
+  }
+  column "pet_count" {
+    null = true
+    type = bigint
+  }
+  as = "SELECT user_id, COUNT(id) AS pet_count FROM pets GROUP BY user_id ORDER BY pet_count DESC LIMIT 10"
+}
+schema "public" {
+}
@@ -0,0 +1,27 @@
+-- atlas:deps customer_count[type=function] top_customers[type=view]
+-- atlas:deps trg_customers_log[type=trigger] customer_logs[type=table]
+
+CREATE TABLE "customers" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"active" boolean,"score" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_customers_deleted_at" ON "customers" ("deleted_at");
+CREATE TABLE "customer_logs" ("id" bigserial,"customer_id" bigint,PRIMARY KEY ("id"));
+CREATE FUNCTION customer_tier(score bigint) RETURNS int LANGUAGE SQL IMMUTABLE AS $$ SELECT CASE WHEN score > 1000 THEN 1 ELSE 2 END $$;
+CREATE VIEW active_customers AS SELECT id, name, score FROM "customers" WHERE active = true;
+CREATE VIEW top_customers AS SELECT id, name, customer_tier(score) AS tier FROM active_customers WHERE score > 100;
+CREATE FUNCTION customer_count() RETURNS bigint LANGUAGE plpgsql AS $$
+DECLARE n bigint;
+BEGIN
+	EXECUTE 'SELECT count(*) FROM top_customers' INTO n;
+	RETURN n;
+END
+$$;
+CREATE OR REPLACE FUNCTION "trg_customers_log_func"()
+RETURNS TRIGGER AS $$
+BEGIN
+	INSERT INTO customer_logs (customer_id) VALUES (NEW.id);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "trg_customers_log"
+AFTER INSERT ON "customers"
+FOR EACH ROW
+EXECUTE FUNCTION "trg_customers_log_func"();
@@ -0,0 +1,51 @@
+table "tickets" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  column "updated_at" {
+    null = true
+    type = timestamptz
+  }
+  column "deleted_at" {
+    null = true
+    type = timestamptz
+  }
+  column "title" {
+    null = true
+    type = text
+  }
+  column "status" {
+    null    = false
+    type    = enum.ticket_status
+    default = "open"
+  }
+  column "previous_status" {
+    null = true
+    type = enum.ticket_status
+  }
+  column "priority" {
+    null = true
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_tickets_deleted_at" {
+    columns = [column.deleted_at]
+  }
+  check "chk_tickets_priority" {
+    expr = "\"priority\" IN (1, 2, 3)"
+  }
+}
+enum "ticket_status" {
+  schema = schema.public
+  values = ["open", "pending", "closed"]
+}
+schema "public" {
+}
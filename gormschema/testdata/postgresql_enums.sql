@@ -0,0 +1,4 @@
+CREATE TYPE "ticket_status" AS ENUM ('open', 'pending', 'closed');
+CREATE TABLE "tickets" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"title" text,"status" "ticket_status" NOT NULL DEFAULT 'open',"previous_status" "ticket_status","priority" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_tickets_deleted_at" ON "tickets" ("deleted_at");
+ALTER TABLE "tickets" ADD CONSTRAINT "chk_tickets_priority" CHECK ("priority" IN (1, 2, 3));
@@ -0,0 +1,24 @@
+-- atlas:pos archive_pets[type=procedure] /internal/testdata/functions/models.go:61
+-- atlas:pos log_pet_history[type=function] /internal/testdata/functions/models.go:39
+-- atlas:pos pets[type=table] /internal/testdata/functions/models.go:11
+
+CREATE TABLE "pets" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"user_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_pets_deleted_at" ON "pets" ("deleted_at");
+CREATE TABLE "pet_histories" ("user_id" bigint,"pet_id" bigint,"created_at" timestamptz,PRIMARY KEY ("user_id","pet_id"));
+CREATE OR REPLACE FUNCTION log_pet_history()
+RETURNS TRIGGER AS $$
+BEGIN
+	INSERT INTO pet_histories (user_id, pet_id, created_at)
+	VALUES (NEW.user_id, NEW.id, NEW.created_at);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE PROCEDURE archive_pets(before timestamptz)
+LANGUAGE SQL
+AS $$
+	UPDATE pets SET deleted_at = now() WHERE created_at < before;
+$$;
+CREATE TRIGGER "trg_insert_pet_history"
+AFTER INSERT ON "pets"
+FOR EACH ROW
+EXECUTE FUNCTION "log_pet_history"();
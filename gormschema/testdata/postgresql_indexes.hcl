@@ -0,0 +1,50 @@
+table "customers" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  column "updated_at" {
+    null = true
+    type = timestamptz
+  }
+  column "deleted_at" {
+    null = true
+    type = timestamptz
+  }
+  column "email" {
+    null = true
+    type = varchar(255)
+  }
+  column "name" {
+    null = true
+    type = varchar(255)
+  }
+  column "country" {
+    null = true
+    type = varchar(2)
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_customers_deleted_at" {
+    columns = [column.deleted_at]
+  }
+  index "idx_customers_email" {
+    unique = true
+    where  = "deleted_at IS NULL"
+    on {
+      expr = "lower(email)"
+    }
+  }
+  index "idx_customers_country" {
+    columns = [column.country]
+    include = [column.name]
+  }
+}
+schema "public" {
+}
@@ -0,0 +1,4 @@
+CREATE TABLE "customers" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"email" varchar(255),"name" varchar(255),"country" varchar(2),PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_customers_deleted_at" ON "customers" ("deleted_at");
+CREATE UNIQUE INDEX "idx_customers_email" ON "customers" ((lower(email))) WHERE deleted_at IS NULL;
+CREATE INDEX "idx_customers_country" ON "customers" USING BTREE ("country") INCLUDE ("name");
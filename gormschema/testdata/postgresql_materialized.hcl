@@ -0,0 +1,51 @@
+table "orders" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  column "updated_at" {
+    null = true
+    type = timestamptz
+  }
+  column "deleted_at" {
+    null = true
+    type = timestamptz
+  }
+  column "customer_id" {
+    null = true
+    type = bigint
+  }
+  column "amount" {
+    null = true
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_orders_deleted_at" {
+    columns = [column.deleted_at]
+  }
+}
+materialized "customer_totals" {
+  schema = schema.public
+  column "customer_id" {
+    null = true
+    type = bigint
+  }
+  column "total" {
+    null = true
+    type = bigint
+  }
+  index "idx_customer_totals_customer_id" {
+    unique  = true
+    columns = [column.customer_id]
+  }
+  as = "SELECT customer_id, SUM(amount) AS total FROM \"orders\" GROUP BY \"customer_id\""
+}
+schema "public" {
+}
@@ -0,0 +1,9 @@
+-- atlas:pos customer_totals[type=materialized] /internal/testdata/materialized/models.go:15
+-- atlas:pos large_orders[type=materialized] /internal/testdata/materialized/models.go:35
+-- atlas:pos orders[type=table] /internal/testdata/materialized/models.go:9
+
+CREATE TABLE "orders" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"customer_id" bigint,"amount" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_orders_deleted_at" ON "orders" ("deleted_at");
+CREATE MATERIALIZED VIEW customer_totals AS SELECT customer_id, SUM(amount) AS total FROM "orders" GROUP BY "customer_id" WITH DATA;
+CREATE UNIQUE INDEX "idx_customer_totals_customer_id" ON "customer_totals" ("customer_id");
+CREATE MATERIALIZED VIEW large_orders AS SELECT id, amount FROM "orders" WHERE amount > 1000 WITH NO DATA;
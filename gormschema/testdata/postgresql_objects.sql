@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS citext;
+CREATE DOMAIN email AS citext CHECK (VALUE ~ '^[^@]+@[^@]+$');
+CREATE SEQUENCE invoice_number_seq START 1000;
+CREATE TABLE "customers" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"email" email,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_customers_deleted_at" ON "customers" ("deleted_at");
+CREATE TABLE "invoices" ("id" bigserial,"number" bigint DEFAULT nextval('invoice_number_seq'),"customer_id" bigint,PRIMARY KEY ("id"));
+ALTER TABLE "invoices" ADD CONSTRAINT "fk_invoices_customer" FOREIGN KEY ("customer_id") REFERENCES "customers"("id");
@@ -0,0 +1,124 @@
+table "tags" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "name" {
+    null = true
+    type = text
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_tags_name" {
+    unique  = true
+    columns = [column.name]
+  }
+}
+table "invoice_tags" {
+  schema = schema.public
+  column "invoice_id" {
+    null = false
+    type = bigint
+  }
+  column "tag_id" {
+    null = false
+    type = bigint
+  }
+  primary_key {
+    columns = [column.invoice_id, column.tag_id]
+  }
+  foreign_key "fk_invoice_tags_invoice" {
+    columns     = [column.invoice_id]
+    ref_columns = [table.invoices.column.id]
+  }
+  foreign_key "fk_invoice_tags_tag" {
+    columns     = [column.tag_id]
+    ref_columns = [table.tags.column.id]
+  }
+}
+table "accounts" {
+  schema = schema.crm
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  column "updated_at" {
+    null = true
+    type = timestamptz
+  }
+  column "deleted_at" {
+    null = true
+    type = timestamptz
+  }
+  column "name" {
+    null = true
+    type = text
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_accounts_deleted_at" {
+    columns = [column.deleted_at]
+  }
+}
+table "invoices" {
+  schema = schema.billing
+  column "id" {
+    null = false
+    type = bigserial
+  }
+  column "created_at" {
+    null = true
+    type = timestamptz
+  }
+  column "updated_at" {
+    null = true
+    type = timestamptz
+  }
+  column "deleted_at" {
+    null = true
+    type = timestamptz
+  }
+  column "amount" {
+    null = true
+    type = bigint
+  }
+  column "account_id" {
+    null = true
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "fk_billing_invoices_account" {
+    columns     = [column.account_id]
+    ref_columns = [table.accounts.column.id]
+  }
+  index "idx_billing_invoices_deleted_at" {
+    columns = [column.deleted_at]
+  }
+}
+view "account_balances" {
+  schema = schema.crm
+  column "account_id" {
+    null = true
+    type = bigint
+  }
+  column "balance" {
+    null = true
+    type = bigint
+  }
+  as = "SELECT account_id, SUM(amount) AS balance FROM \"billing\".\"invoices\" GROUP BY \"account_id\""
+}
+schema "public" {
+}
+schema "crm" {
+}
+schema "billing" {
+}
@@ -0,0 +1,20 @@
+-- atlas:pos billing.invoices[type=table] /internal/testdata/multischema/models.go:16
+-- atlas:pos crm.account_balances[type=view] /internal/testdata/multischema/models.go:34
+-- atlas:pos crm.accounts[type=table] /internal/testdata/multischema/models.go:10
+
+CREATE SCHEMA IF NOT EXISTS "billing";
+CREATE SCHEMA IF NOT EXISTS "crm";
+CREATE TABLE "crm"."accounts" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_accounts_deleted_at" ON "crm"."accounts" ("deleted_at");
+CREATE TABLE "billing"."invoices" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"amount" bigint,"account_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_billing_invoices_deleted_at" ON "billing"."invoices" ("deleted_at");
+CREATE TABLE "tags" ("id" bigserial,"name" text,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_name" ON "tags" ("name");
+CREATE TABLE "invoice_tags" ("invoice_id" bigint,"tag_id" bigint,PRIMARY KEY ("invoice_id","tag_id"));
+CREATE TABLE "billing"."payments" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"amount" bigint,"account_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_payments_deleted_at" ON "billing"."payments" ("deleted_at");
+CREATE VIEW crm.account_balances AS SELECT account_id, SUM(amount) AS balance FROM "billing"."invoices" GROUP BY "account_id";
+ALTER TABLE "billing"."invoices" ADD CONSTRAINT "fk_billing_invoices_account" FOREIGN KEY ("account_id") REFERENCES "crm"."accounts"("id");
+ALTER TABLE "invoice_tags" ADD CONSTRAINT "fk_invoice_tags_invoice" FOREIGN KEY ("invoice_id") REFERENCES "billing"."invoices"("id");
+ALTER TABLE "invoice_tags" ADD CONSTRAINT "fk_invoice_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id");
+ALTER TABLE "billing"."payments" ADD CONSTRAINT "fk_payments_account" FOREIGN KEY ("account_id") REFERENCES "crm"."accounts"("id");
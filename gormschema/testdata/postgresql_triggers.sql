@@ -0,0 +1,67 @@
+CREATE TABLE "pets" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"user_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_pets_deleted_at" ON "pets" ("deleted_at");
+CREATE TABLE "pet_histories" ("user_id" bigint,"pet_id" bigint,"created_at" timestamptz,PRIMARY KEY ("user_id","pet_id"));
+CREATE TABLE "toys" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_toys_deleted_at" ON "toys" ("deleted_at");
+CREATE TABLE "categories" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"parent_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_categories_deleted_at" ON "categories" ("deleted_at");
+CREATE OR REPLACE FUNCTION "trg_insert_pet_history_func"()
+RETURNS TRIGGER AS $$
+BEGIN
+	INSERT INTO pet_histories (user_id, pet_id, created_at) VALUES (NEW.user_id, NEW.id, NEW.created_at);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "trg_insert_pet_history"
+AFTER INSERT ON "pets"
+FOR EACH ROW
+EXECUTE FUNCTION "trg_insert_pet_history_func"();
+CREATE OR REPLACE FUNCTION "trg_adding_heart_on_pet_func"()
+RETURNS TRIGGER AS $$
+BEGIN
+	NEW.name := NEW.name || ' <3';
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "trg_adding_heart_on_pet"
+BEFORE INSERT ON "pets"
+FOR EACH ROW
+EXECUTE FUNCTION "trg_adding_heart_on_pet_func"();
+CREATE OR REPLACE FUNCTION "trg_toys_updated_at_func"()
+RETURNS TRIGGER AS $$
+BEGIN
+	UPDATE toys SET updated_at = CURRENT_TIMESTAMP;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "trg_toys_updated_at"
+AFTER INSERT OR UPDATE ON "toys"
+FOR EACH STATEMENT
+EXECUTE FUNCTION "trg_toys_updated_at_func"();
+CREATE OR REPLACE FUNCTION "trg_categories_normalize_func"()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.parent_id = NEW.id THEN
+		RETURN NULL;
+	END IF;
+	NEW.name := trim(NEW.name);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "trg_categories_normalize"
+BEFORE INSERT ON "categories"
+FOR EACH ROW
+EXECUTE FUNCTION "trg_categories_normalize_func"();
+CREATE OR REPLACE FUNCTION "trg_categories_protect_root_func"()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF OLD.parent_id = 0 THEN
+		RAISE EXCEPTION 'cannot delete a root category';
+	END IF;
+	RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "trg_categories_protect_root"
+BEFORE DELETE ON "categories"
+FOR EACH ROW
+EXECUTE FUNCTION "trg_categories_protect_root_func"();
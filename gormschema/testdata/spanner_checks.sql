@@ -0,0 +1,4 @@
+CREATE TABLE `products` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`created_at` TIMESTAMP,`updated_at` TIMESTAMP,`deleted_at` TIMESTAMP,`sku` STRING(8),`price` INT64,`discount_price` INT64) PRIMARY KEY (`id`);
+CREATE INDEX `idx_products_deleted_at` ON `products`(`deleted_at`);
+ALTER TABLE `products` ADD CONSTRAINT `chk_products_discount` CHECK (discount_price >= 0 AND discount_price < price);
+ALTER TABLE `products` ADD CONSTRAINT `chk_products_sku` CHECK (char_length(sku) = 8);
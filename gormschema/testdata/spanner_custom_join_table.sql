@@ -0,0 +1,11 @@
+-- atlas:pos addresses[type=table] /internal/testdata/customjointable/models.go:17
+-- atlas:pos people[type=table] /internal/testdata/customjointable/models.go:11
+-- atlas:pos person_addresses[type=table] /internal/testdata/customjointable/models.go:22
+-- atlas:pos top_crowded_addresses[type=view] /internal/testdata/customjointable/models.go:29
+
+CREATE TABLE `addresses` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`name` STRING(MAX)) PRIMARY KEY (`id`);
+CREATE TABLE `people` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`name` STRING(MAX)) PRIMARY KEY (`id`);
+CREATE TABLE `person_addresses` (`person_id` INT64,`address_id` INT64,`created_at` TIMESTAMP,`deleted_at` TIMESTAMP) PRIMARY KEY (`person_id`,`address_id`);
+CREATE VIEW top_crowded_addresses SQL SECURITY INVOKER AS SELECT address_id, COUNT(person_id) AS count FROM `person_addresses` GROUP BY `address_id` ORDER BY count DESC LIMIT 10;
+ALTER TABLE `person_addresses` ADD CONSTRAINT `fk_person_addresses_address` FOREIGN KEY (`address_id`) REFERENCES `addresses`(`id`);
+ALTER TABLE `person_addresses` ADD CONSTRAINT `fk_person_addresses_person` FOREIGN KEY (`person_id`) REFERENCES `people`(`id`);
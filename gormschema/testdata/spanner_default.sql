@@ -0,0 +1,22 @@
+CREATE TABLE `events` (`eventId` STRING(191),`locationId` STRING(191)) PRIMARY KEY (`eventId`);
+CREATE UNIQUE INDEX `idx_events_location_id` ON `events`(`locationId`);
+CREATE TABLE `locations` (`locationId` STRING(191),`eventId` STRING(191)) PRIMARY KEY (`locationId`);
+CREATE UNIQUE INDEX `idx_locations_event_id` ON `locations`(`eventId`);
+CREATE TABLE `user_pet_histories` (`user_id` INT64,`pet_id` INT64,`created_at` TIMESTAMP) PRIMARY KEY (`user_id`,`pet_id`);
+CREATE TABLE `users` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`created_at` TIMESTAMP,`updated_at` TIMESTAMP,`deleted_at` TIMESTAMP,`name` STRING(MAX),`age` INT64) PRIMARY KEY (`id`);
+CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`);
+CREATE TABLE `hobbies` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`name` STRING(MAX)) PRIMARY KEY (`id`);
+CREATE TABLE `user_hobbies` (`hobby_id` INT64,`user_id` INT64) PRIMARY KEY (`hobby_id`,`user_id`);
+CREATE TABLE `pets` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`created_at` TIMESTAMP,`updated_at` TIMESTAMP,`deleted_at` TIMESTAMP,`name` STRING(MAX),`user_id` INT64) PRIMARY KEY (`id`);
+CREATE INDEX `idx_pets_deleted_at` ON `pets`(`deleted_at`);
+CREATE OR REPLACE VIEW working_aged_users
+SQL SECURITY INVOKER
+AS
+SELECT u.name, u.age
+FROM users AS u
+WHERE u.age BETWEEN 18 AND 65;
+ALTER TABLE `events` ADD CONSTRAINT `fk_locations_event` FOREIGN KEY (`locationId`) REFERENCES `locations`(`locationId`);
+ALTER TABLE `locations` ADD CONSTRAINT `fk_events_location` FOREIGN KEY (`eventId`) REFERENCES `events`(`eventId`);
+ALTER TABLE `user_hobbies` ADD CONSTRAINT `fk_user_hobbies_hobby` FOREIGN KEY (`hobby_id`) REFERENCES `hobbies`(`id`);
+ALTER TABLE `user_hobbies` ADD CONSTRAINT `fk_user_hobbies_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`);
+ALTER TABLE `pets` ADD CONSTRAINT `fk_users_pets` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`);
@@ -0,0 +1,4 @@
+CREATE TABLE `customers` (`id` INT64 GENERATED BY DEFAULT AS IDENTITY (BIT_REVERSED_POSITIVE),`created_at` TIMESTAMP,`updated_at` TIMESTAMP,`deleted_at` TIMESTAMP,`email` STRING(255),`name` STRING(255),`country` STRING(2)) PRIMARY KEY (`id`);
+CREATE INDEX `idx_customers_deleted_at` ON `customers`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_customers_email` ON `customers` (`email`);
+CREATE INDEX `idx_customers_country` ON `customers` (`country`) STORING (`name`);
@@ -0,0 +1,4 @@
+CREATE TABLE `events` (`eventId` STRING(191),`locationId` STRING(191)) PRIMARY KEY (`eventId`);
+CREATE UNIQUE INDEX `idx_events_location_id` ON `events`(`locationId`);
+CREATE TABLE `locations` (`locationId` STRING(191),`eventId` STRING(191)) PRIMARY KEY (`locationId`);
+CREATE UNIQUE INDEX `idx_locations_event_id` ON `locations`(`eventId`);
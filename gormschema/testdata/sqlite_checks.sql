@@ -0,0 +1,2 @@
+CREATE TABLE `products` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`sku` text,`price` integer,`discount_price` integer,CONSTRAINT `chk_products_discount` CHECK (discount_price >= 0 AND discount_price < price),CONSTRAINT `chk_products_sku` CHECK (length(sku) = 8));
+CREATE INDEX `idx_products_deleted_at` ON `products`(`deleted_at`);
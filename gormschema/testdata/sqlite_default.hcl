@@ -0,0 +1,159 @@
+table "events" {
+  schema = schema.main
+  column "eventId" {
+    null = false
+    type = text
+  }
+  column "locationId" {
+    null = true
+    type = text
+  }
+  primary_key {
+    columns = [column.eventId]
+  }
+  foreign_key "fk_locations_event" {
+    columns     = [column.locationId]
+    ref_columns = [table.locations.column.locationId]
+  }
+  index "idx_events_location_id" {
+    unique  = true
+    columns = [column.locationId]
+  }
+}
+table "locations" {
+  schema = schema.main
+  column "locationId" {
+    null = false
+    type = text
+  }
+  column "eventId" {
+    null = true
+    type = text
+  }
+  primary_key {
+    columns = [column.locationId]
+  }
+  foreign_key "fk_events_location" {
+    columns     = [column.eventId]
+    ref_columns = [table.events.column.eventId]
+  }
+  index "idx_locations_event_id" {
+    unique  = true
+    columns = [column.eventId]
+  }
+}
+table "user_pet_histories" {
+  schema = schema.main
+  column "user_id" {
+    null = false
+    type = integer
+  }
+  column "pet_id" {
+    null = false
+    type = integer
+  }
+  column "created_at" {
+    null = true
+    type = datetime
+  }
+  primary_key {
+    columns = [column.user_id, column.pet_id]
+  }
+}
+table "users" {
+  schema = schema.main
+  column "id" {
+    null           = false
+    type           = integer
+    auto_increment = true
+  }
+  column "created_at" {
+    null = true
+    type = datetime
+  }
+  column "updated_at" {
+    null = true
+    type = datetime
+  }
+  column "deleted_at" {
+    null = true
+    type = datetime
+  }
+  column "name" {
+    null = true
+    type = text
+  }
+  column "age" {
+    null = true
+    type = integer
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx_users_deleted_at" {
+    columns = [column.deleted_at]
+  }
+}
+table "hobbies" {
+  schema = schema.main
+  column "id" {
+    null           = false
+    type           = integer
+    auto_increment = true
+  }
+  column "name" {
+    null = true
+    type = text
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+table "user_hobbies" {
+  schema = schema.main
+  column "hobby_id" {
+    null = false
+    type = integer
+  }
+  column "user_id" {
+    null = false
+    type = integer
+  }
+  primary_key {
+    columns = [column.hobby_id, column.user_id]
+  }
+  foreign_key "fk_user_hobbies_hobby" {
+    columns     = [column.hobby_id]
+    ref_columns = [table.hobbies.column.id]
+  }
+  foreign_key "fk_user_hobbies_user" {
+    columns     = [column.user_id]
+    ref_columns = [table.users.column.id]
+  }
+}
+view "working_aged_users" {
+  schema = schema.main
+  column "name" {
+    null = true
+    type = text
+  }
+  column "age" {
+    null = true
+    type = integer
+  }
+  as = "SELECT name, age FROM `users` WHERE age BETWEEN 18 AND 65"
+}
+view "top_pet_owners" {
+  schema = schema.main
+  column "name" {
+    null = true
+    type = text
+  }
+  column "pet_count" {
+    null = true
+    type = integer
+  }
+  as = "SELECT user_id, COUNT(id) AS pet_count FROM pets GROUP BY user_id ORDER BY pet_count DESC LIMIT 10"
+}
+schema "main" {
+}
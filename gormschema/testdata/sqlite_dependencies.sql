@@ -0,0 +1,13 @@
+-- atlas:deps trg_customers_log[type=trigger] customer_logs[type=table]
+
+CREATE TABLE `customers` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`active` numeric,`score` integer);
+CREATE INDEX `idx_customers_deleted_at` ON `customers`(`deleted_at`);
+CREATE TABLE `customer_logs` (`id` integer PRIMARY KEY AUTOINCREMENT,`customer_id` integer);
+CREATE VIEW active_customers AS SELECT id, name, score FROM `customers` WHERE active = true;
+CREATE VIEW top_customers AS SELECT id, name, 1 AS tier FROM active_customers WHERE score > 100;
+CREATE TRIGGER `trg_customers_log`
+AFTER INSERT ON `customers`
+FOR EACH ROW
+BEGIN
+	INSERT INTO customer_logs (customer_id) VALUES (NEW.id);
+END;
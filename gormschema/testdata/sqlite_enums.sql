@@ -0,0 +1,2 @@
+CREATE TABLE `tickets` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`title` text,`status` text NOT NULL DEFAULT "open",`previous_status` text,`priority` integer,CONSTRAINT `chk_tickets_status` CHECK (`status` IN ('open', 'pending', 'closed')),CONSTRAINT `chk_tickets_previous_status` CHECK (`previous_status` IN ('open', 'pending', 'closed')),CONSTRAINT `chk_tickets_priority` CHECK (`priority` IN (1, 2, 3)));
+CREATE INDEX `idx_tickets_deleted_at` ON `tickets`(`deleted_at`);
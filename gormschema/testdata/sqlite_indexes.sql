@@ -0,0 +1,4 @@
+CREATE TABLE `customers` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`email` text,`name` text,`country` text);
+CREATE INDEX `idx_customers_deleted_at` ON `customers`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_customers_email` ON `customers` ((lower(email))) WHERE deleted_at IS NULL;
+CREATE INDEX `idx_customers_country` ON `customers` (`country`,`name`);
@@ -0,0 +1,4 @@
+CREATE TABLE "products" ("id" bigint IDENTITY(1,1),"created_at" datetimeoffset,"updated_at" datetimeoffset,"deleted_at" datetimeoffset,"sku" nvarchar(8),"price" bigint,"discount_price" bigint,PRIMARY KEY ("id"));
+CREATE INDEX "idx_products_deleted_at" ON "products"("deleted_at");
+ALTER TABLE "products" ADD CONSTRAINT "chk_products_discount" CHECK (discount_price >= 0 AND discount_price < price);
+ALTER TABLE "products" ADD CONSTRAINT "chk_products_sku" CHECK (LEN(sku) = 8);
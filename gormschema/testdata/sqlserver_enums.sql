@@ -0,0 +1,5 @@
+CREATE TABLE "tickets" ("id" bigint IDENTITY(1,1),"created_at" datetimeoffset,"updated_at" datetimeoffset,"deleted_at" datetimeoffset,"title" nvarchar(MAX),"status" nvarchar(MAX) NOT NULL DEFAULT 'open',"previous_status" nvarchar(MAX),"priority" bigint,PRIMARY KEY ("id"));
+CREATE INDEX "idx_tickets_deleted_at" ON "tickets"("deleted_at");
+ALTER TABLE "tickets" ADD CONSTRAINT "chk_tickets_status" CHECK ("status" IN ('open', 'pending', 'closed'));
+ALTER TABLE "tickets" ADD CONSTRAINT "chk_tickets_previous_status" CHECK ("previous_status" IN ('open', 'pending', 'closed'));
+ALTER TABLE "tickets" ADD CONSTRAINT "chk_tickets_priority" CHECK ("priority" IN (1, 2, 3));
@@ -0,0 +1,12 @@
+-- atlas:pos archive_pets[type=procedure] /internal/testdata/functions/models.go:61
+-- atlas:pos log_pet_history[type=function] /internal/testdata/functions/models.go:39
+-- atlas:pos pets[type=table] /internal/testdata/functions/models.go:11
+
+CREATE TABLE "pets" ("id" bigint IDENTITY(1,1),"created_at" datetimeoffset,"updated_at" datetimeoffset,"deleted_at" datetimeoffset,"name" nvarchar(MAX),"user_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX "idx_pets_deleted_at" ON "pets"("deleted_at");
+CREATE TABLE "pet_histories" ("user_id" bigint,"pet_id" bigint,"created_at" datetimeoffset,PRIMARY KEY ("user_id","pet_id"));
+CREATE PROCEDURE archive_pets @before datetimeoffset
+AS
+BEGIN
+	UPDATE pets SET deleted_at = SYSDATETIMEOFFSET() WHERE created_at < @before;
+END;
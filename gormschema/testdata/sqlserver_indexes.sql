@@ -0,0 +1,4 @@
+CREATE TABLE "customers" ("id" bigint IDENTITY(1,1),"created_at" datetimeoffset,"updated_at" datetimeoffset,"deleted_at" datetimeoffset,"email" nvarchar(255),"name" nvarchar(255),"country" nvarchar(2),PRIMARY KEY ("id"));
+CREATE INDEX "idx_customers_deleted_at" ON "customers"("deleted_at");
+CREATE UNIQUE INDEX "idx_customers_email" ON "customers" ("email") WHERE deleted_at IS NULL;
+CREATE NONCLUSTERED INDEX "idx_customers_country" ON "customers" ("country") INCLUDE ("name");
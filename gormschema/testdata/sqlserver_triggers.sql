@@ -0,0 +1,31 @@
+CREATE TABLE "pets" ("id" bigint IDENTITY(1,1),"created_at" datetimeoffset,"updated_at" datetimeoffset,"deleted_at" datetimeoffset,"name" nvarchar(MAX),"user_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX "idx_pets_deleted_at" ON "pets"("deleted_at");
+CREATE TABLE "pet_histories" ("user_id" bigint,"pet_id" bigint,"created_at" datetimeoffset,PRIMARY KEY ("user_id","pet_id"));
+CREATE TABLE "toys" ("id" bigint IDENTITY(1,1),"created_at" datetimeoffset,"updated_at" datetimeoffset,"deleted_at" datetimeoffset,"name" nvarchar(MAX),PRIMARY KEY ("id"));
+CREATE INDEX "idx_toys_deleted_at" ON "toys"("deleted_at");
+CREATE TRIGGER "trg_insert_pet_history"
+ON "pets"
+AFTER INSERT
+AS
+BEGIN
+	INSERT INTO pet_histories (user_id, pet_id, created_at)
+	SELECT inserted.user_id, inserted.id, GETDATE()
+	FROM inserted
+	WHERE inserted.user_id IS NOT NULL;
+END;
+CREATE TRIGGER "trg_adding_heart_on_pet"
+ON "pets"
+INSTEAD OF INSERT
+AS
+BEGIN
+	INSERT INTO pets (name, user_id)
+	SELECT CONCAT(inserted.name, ' <3'), inserted.user_id
+	FROM inserted;
+END;
+CREATE TRIGGER "trg_toys_updated_at"
+ON "toys"
+AFTER INSERT, UPDATE
+AS
+BEGIN
+	UPDATE toys SET updated_at = CURRENT_TIMESTAMP;
+END;
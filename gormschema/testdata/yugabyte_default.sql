@@ -0,0 +1,9 @@
+CREATE TABLE "accounts" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"number" integer,"priority" smallint,"balance" bigint,"owner" varchar(255),PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_accounts_number" ON "accounts" ("number");
+CREATE INDEX IF NOT EXISTS "idx_accounts_deleted_at" ON "accounts" ("deleted_at");
+CREATE TABLE "transfers" ("id" varchar(36),"account_id" bigint,"amount" integer,"fee" bigint,PRIMARY KEY ("id"));
+CREATE TABLE "customers" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"email" varchar(255),"name" varchar(255),"country" varchar(2),PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_customers_deleted_at" ON "customers" ("deleted_at");
+CREATE UNIQUE INDEX "idx_customers_email" ON "customers" ((lower(email))) WHERE deleted_at IS NULL;
+CREATE INDEX "idx_customers_country" ON "customers" USING BTREE ("country") INCLUDE ("name");
+ALTER TABLE "transfers" ADD CONSTRAINT "fk_transfers_account" FOREIGN KEY ("account_id") REFERENCES "accounts"("id");
@@ -0,0 +1,211 @@
+package gormschema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type (
+	// TriggerTiming defines when a trigger fires relative to its events.
+	TriggerTiming string
+	// TriggerEvent defines an operation that fires a trigger.
+	TriggerEvent string
+	// triggerDef holds the typed definition of a trigger.
+	triggerDef struct {
+		name       string
+		function   string
+		timing     TriggerTiming
+		events     []TriggerEvent
+		forEachRow bool
+		body       string
+	}
+)
+
+// Trigger timings and events.
+const (
+	TriggerBefore    TriggerTiming = "BEFORE"
+	TriggerAfter     TriggerTiming = "AFTER"
+	TriggerInsteadOf TriggerTiming = "INSTEAD OF"
+
+	TriggerInsert TriggerEvent = "INSERT"
+	TriggerUpdate TriggerEvent = "UPDATE"
+	TriggerDelete TriggerEvent = "DELETE"
+)
+
+// TriggerName sets the name of the trigger.
+func TriggerName(name string) TriggerOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.trigger.name = name
+	})
+}
+
+// TriggerTime sets when the trigger fires: before, after or instead of its events.
+// SQLite supports INSTEAD OF triggers only on views.
+func TriggerTime(t TriggerTiming) TriggerOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.trigger.timing = t
+	})
+}
+
+// TriggerEvents sets the operations that fire the trigger.
+// MySQL and SQLite triggers support a single event.
+func TriggerEvents(events ...TriggerEvent) TriggerOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.trigger.events = append(b.trigger.events, events...)
+	})
+}
+
+// ForEachRow makes the trigger fire once for each affected row. It is not supported by SQL Server,
+// whose triggers fire once per statement and access the affected rows using the inserted and deleted
+// tables.
+func ForEachRow() TriggerOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.trigger.forEachRow = true
+	})
+}
+
+// TriggerBody sets the statements executed by the trigger. On PostgreSQL, the body
+// is wrapped in a trigger function that returns NEW (or OLD for delete triggers),
+// unless the last statement of the body is a RETURN statement.
+func TriggerBody(body string) TriggerOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.trigger.body = body
+	})
+}
+
+// TriggerFunction sets the name of the function executed by a PostgreSQL trigger. The default
+// name is the trigger name suffixed with "_func", in the schema of the table. If the trigger has
+// no body, the function is expected to be defined separately, for example, by a FunctionDefiner.
+func TriggerFunction(name string) TriggerOption {
+	return schemaOption(func(b *schemaBuilder) {
+		b.trigger.function = name
+	})
+}
+
+var (
+	// returnRe matches a PL/pgSQL body whose last statement is a RETURN statement.
+	returnRe = regexp.MustCompile(`(?i)(?:^|;)\s*RETURN\b[^;]*;?\s*$`)
+	// triggerNameRe extracts the name of a trigger from its CREATE statement, which may
+	// follow the definition of the trigger function.
+	triggerNameRe = regexp.MustCompile(`(?ism)^\s*CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?TRIGGER\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)`)
+)
+
+// triggerName returns the name of the trigger of the builder, if it is known.
+func (b *schemaBuilder) triggerName() string {
+	if b.createStmt == "" {
+		return b.trigger.name
+	}
+	if matches := triggerNameRe.FindStringSubmatch(b.createStmt); matches != nil {
+		return unquoter.Replace(matches[1])
+	}
+	return ""
+}
+
+// triggerStmts returns the statements that create the trigger of the builder.
+func (b *schemaBuilder) triggerStmts() ([]string, error) {
+	if b.createStmt != "" {
+		return []string{b.createStmt}, nil
+	}
+	var (
+		t       = b.trigger
+		dialect = b.db.Dialector.Name()
+		q       = b.db.Statement.Quote
+	)
+	switch {
+	// An empty trigger definition, e.g. CreateStmt(""), creates nothing.
+	case t.name == "" && t.timing == "" && len(t.events) == 0 && t.body == "":
+		return nil, nil
+	case t.name == "":
+		return nil, fmt.Errorf("trigger on %s: missing name", b.tableName)
+	case t.timing == "" || len(t.events) == 0:
+		return nil, fmt.Errorf("trigger %q on %s: missing timing or events", t.name, b.tableName)
+	// PostgreSQL triggers may execute an existing function instead of a body.
+	case t.body == "" && (dialect != "postgres" || t.function == ""):
+		return nil, fmt.Errorf("trigger %q on %s: missing body", t.name, b.tableName)
+	case dialect == "spanner":
+		return nil, fmt.Errorf("trigger %q on %s: triggers are not supported by %s", t.name, b.tableName, dialect)
+	case len(t.events) > 1 && (dialect == "mysql" || dialect == "sqlite"):
+		return nil, fmt.Errorf("trigger %q on %s: multiple events are not supported by %s", t.name, b.tableName, dialect)
+	case t.timing == TriggerInsteadOf && dialect == "mysql", t.timing == TriggerBefore && dialect == "sqlserver":
+		return nil, fmt.Errorf("trigger %q on %s: %s triggers are not supported by %s", t.name, b.tableName, t.timing, dialect)
+	case t.timing == TriggerInsteadOf && dialect == "sqlite" && !b.onView:
+		return nil, fmt.Errorf("trigger %q on %s: %s triggers on tables are not supported by %s", t.name, b.tableName, t.timing, dialect)
+	case t.forEachRow && dialect == "sqlserver":
+		return nil, fmt.Errorf("trigger %q on %s: row-level triggers are not supported by %s", t.name, b.tableName, dialect)
+	}
+	events := make([]string, len(t.events))
+	for i, e := range t.events {
+		events[i] = string(e)
+	}
+	var body string
+	if t.body != "" {
+		body = indentBody(t.body)
+	}
+	switch dialect {
+	case "postgres":
+		fn := t.function
+		if fn == "" {
+			fn = t.name + "_func"
+			// The function is created in the schema of the table.
+			if i := strings.LastIndexByte(b.tableName, '.'); i != -1 {
+				fn = b.tableName[:i+1] + fn
+			}
+		}
+		level := "STATEMENT"
+		if t.forEachRow {
+			level = "ROW"
+		}
+		trigger := fmt.Sprintf("CREATE TRIGGER %s\n%s %s ON %s\nFOR EACH %s\nEXECUTE FUNCTION %s()", q(t.name), t.timing, strings.Join(events, " OR "), q(b.tableName), level, q(fn))
+		if t.body == "" {
+			return []string{trigger}, nil
+		}
+		if !returnRe.MatchString(t.body) {
+			ret := "NEW"
+			if len(t.events) == 1 && t.events[0] == TriggerDelete {
+				ret = "OLD"
+			}
+			body += "\n\tRETURN " + ret + ";"
+		}
+		return []string{
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s()\nRETURNS TRIGGER AS $$\nBEGIN\n%s\nEND;\n$$ LANGUAGE plpgsql", q(fn), body),
+			trigger,
+		}, nil
+	case "sqlserver":
+		return []string{
+			fmt.Sprintf("CREATE TRIGGER %s\nON %s\n%s %s\nAS\nBEGIN\n%s\nEND", q(t.name), q(b.tableName), t.timing, strings.Join(events, ", "), body),
+		}, nil
+	default:
+		var s strings.Builder
+		fmt.Fprintf(&s, "CREATE TRIGGER %s\n%s %s ON %s\n", q(t.name), t.timing, events[0], q(b.tableName))
+		// FOR EACH ROW is mandatory on MySQL.
+		if t.forEachRow || dialect == "mysql" {
+			s.WriteString("FOR EACH ROW\n")
+		}
+		fmt.Fprintf(&s, "BEGIN\n%s\nEND", body)
+		return []string{s.String()}, nil
+	}
+}
+
+// indentBody indents the lines of a trigger body and terminates its last statement.
+func indentBody(body string) string {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	// Remove the indentation shared by the following lines, such as the indentation of a raw string literal.
+	var prefix string
+	for i, l := range lines[1:] {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if i == 0 || len(indent) < len(prefix) {
+			prefix = indent
+		}
+	}
+	for i, l := range lines {
+		lines[i] = "\t" + strings.TrimPrefix(l, prefix)
+	}
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, ";") {
+		lines[len(lines)-1] = last + ";"
+	}
+	return strings.Join(lines, "\n")
+}
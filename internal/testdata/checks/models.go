@@ -0,0 +1,28 @@
+package checks
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Product struct {
+	gorm.Model
+	SKU           string `gorm:"size:8"`
+	Price         int
+	DiscountPrice int
+}
+
+func (Product) Checks(dialect string) []gormschema.Check {
+	length := "char_length(sku) = 8"
+	switch dialect {
+	case "sqlite":
+		length = "length(sku) = 8"
+	case "sqlserver":
+		length = "LEN(sku) = 8"
+	}
+	return []gormschema.Check{
+		gormschema.NewCheck("chk_products_discount", "discount_price >= 0 AND discount_price < price"),
+		gormschema.NewCheck("chk_products_sku", length),
+	}
+}
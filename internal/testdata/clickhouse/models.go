@@ -0,0 +1,38 @@
+package clickhouse
+
+import (
+	"time"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Event struct {
+	UserID    uint64
+	Name      string `gorm:"type:LowCardinality(String)"`
+	CreatedAt time.Time
+}
+
+func (Event) Engine(string) gormschema.Engine {
+	return gormschema.NewEngine("MergeTree()",
+		gormschema.EngineOrderBy("user_id", "created_at"),
+		gormschema.EnginePartitionBy("toYYYYMM(created_at)"),
+		gormschema.EngineTTL("created_at + INTERVAL 1 YEAR"),
+	)
+}
+
+type PageView struct {
+	ID        uint64 `gorm:"primaryKey"`
+	Path      string
+	Version   uint64
+	CreatedAt time.Time
+}
+
+func (PageView) Engine(string) gormschema.Engine {
+	return gormschema.NewEngine("ReplacingMergeTree(version)", gormschema.EngineOrderBy("id"))
+}
+
+// Session uses the default engine.
+type Session struct {
+	ID        string `gorm:"type:UUID"`
+	StartedAt time.Time
+}
@@ -32,6 +32,13 @@ type TopCrowdedAddresses struct {
 }
 
 func (TopCrowdedAddresses) ViewDef(dialect string) []gormschema.ViewOption {
+	if dialect == "spanner" {
+		return []gormschema.ViewOption{
+			gormschema.BuildStmt(func(db *gorm.DB) *gorm.DB {
+				return db.Table("person_addresses").Select("address_id, COUNT(person_id) AS count").Group("address_id").Order("count DESC").Limit(10)
+			}),
+		}
+	}
 	return []gormschema.ViewOption{
 		gormschema.CreateStmt("CREATE VIEW top_crowded_addresses AS SELECT address_id, COUNT(person_id) AS count FROM person_addresses GROUP BY address_id ORDER BY count DESC LIMIT 10"),
 	}
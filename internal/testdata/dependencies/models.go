@@ -0,0 +1,137 @@
+package dependencies
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Customer struct {
+	gorm.Model
+	Name   string
+	Active bool
+	Score  int
+}
+
+type CustomerLog struct {
+	ID         uint
+	CustomerID uint
+}
+
+func (Customer) Triggers(dialect string) []gormschema.Trigger {
+	opts := []gormschema.TriggerOption{
+		gormschema.TriggerName("trg_customers_log"),
+		gormschema.TriggerTime(gormschema.TriggerAfter),
+		gormschema.TriggerEvents(gormschema.TriggerInsert),
+		gormschema.DependsOn(CustomerLog{}),
+	}
+	// SQL Server triggers fire once per statement.
+	if dialect == "sqlserver" {
+		opts = append(opts, gormschema.TriggerBody("INSERT INTO customer_logs (customer_id) SELECT id FROM inserted"))
+	} else {
+		opts = append(opts, gormschema.ForEachRow(), gormschema.TriggerBody("INSERT INTO customer_logs (customer_id) VALUES (NEW.id)"))
+	}
+	return []gormschema.Trigger{gormschema.NewTrigger(opts...)}
+}
+
+// ActiveCustomer is a view of the customers table.
+type ActiveCustomer struct {
+	ID    uint
+	Name  string
+	Score int
+}
+
+func (ActiveCustomer) ViewDef(dialect string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{
+		gormschema.BuildStmt(func(db *gorm.DB) *gorm.DB {
+			return db.Model(&Customer{}).Where("active = ?", true).Select("id, name, score")
+		}),
+	}
+}
+
+// TopCustomer is a view of the active_customers view,
+// which uses the customer_tier function on PostgreSQL.
+type TopCustomer struct {
+	ID   uint
+	Name string
+	Tier int
+}
+
+func (TopCustomer) ViewDef(dialect string) []gormschema.ViewOption {
+	tier := "1"
+	if dialect == "postgres" {
+		tier = "customer_tier(score)"
+	}
+	return []gormschema.ViewOption{
+		gormschema.CreateStmt("CREATE VIEW top_customers AS SELECT id, name, " + tier + " AS tier FROM active_customers WHERE score > 100"),
+	}
+}
+
+type CustomerTier struct{}
+
+func (CustomerTier) FunctionDef(dialect string) []gormschema.FunctionOption {
+	var stmt string
+	if dialect == "postgres" {
+		stmt = "CREATE FUNCTION customer_tier(score bigint) RETURNS int LANGUAGE SQL IMMUTABLE AS $$ SELECT CASE WHEN score > 1000 THEN 1 ELSE 2 END $$"
+	}
+	return []gormschema.FunctionOption{gormschema.CreateStmt(stmt)}
+}
+
+// CustomerCount references the top_customers view using dynamic SQL,
+// therefore, its dependency cannot be inferred from its statement.
+type CustomerCount struct{}
+
+func (CustomerCount) FunctionDef(dialect string) []gormschema.FunctionOption {
+	var stmt string
+	if dialect == "postgres" {
+		stmt = `CREATE FUNCTION customer_count() RETURNS bigint LANGUAGE plpgsql AS $$
+DECLARE n bigint;
+BEGIN
+	EXECUTE 'SELECT count(*) FROM top_customers' INTO n;
+	RETURN n;
+END
+$$`
+	}
+	return []gormschema.FunctionOption{
+		gormschema.CreateStmt(stmt),
+		gormschema.DependsOn(TopCustomer{}),
+	}
+}
+
+// Ping and Pong are views that reference each other.
+type (
+	Ping struct{ ID uint }
+	Pong struct{ ID uint }
+)
+
+func (Ping) ViewDef(string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{gormschema.CreateStmt("CREATE VIEW pings AS SELECT id FROM pongs")}
+}
+
+func (Pong) ViewDef(string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{gormschema.CreateStmt("CREATE VIEW pongs AS SELECT id FROM pings")}
+}
+
+// Score and Tier are views named after columns, which do not reference each other.
+type (
+	Score struct {
+		ID   uint
+		Tier int
+	}
+	Tier struct {
+		ID    uint
+		Score int
+	}
+)
+
+func (Score) TableName() string { return "score" }
+
+func (Score) ViewDef(string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{gormschema.CreateStmt("CREATE VIEW score AS SELECT id, score / 100 AS tier FROM customers")}
+}
+
+func (Tier) TableName() string { return "tier" }
+
+func (Tier) ViewDef(string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{gormschema.CreateStmt("CREATE VIEW tier AS SELECT id, score FROM customers WHERE score > 0")}
+}
@@ -0,0 +1,39 @@
+package embedded
+
+import (
+	g "gorm.io/gorm"
+)
+
+// BaseModel is embedded by the models below.
+//
+//atlas:ignore
+type BaseModel struct {
+	g.Model
+}
+
+type Author struct {
+	BaseModel
+	Name string
+}
+
+type Book struct {
+	g.Model
+	Title    string
+	AuthorID uint
+	Author   Author
+}
+
+type Review struct {
+	ID     uint
+	Body   string
+	BookID uint
+}
+
+func (Review) TableName() string {
+	return "book_reviews"
+}
+
+// Page is a generic type and cannot be loaded.
+type Page[T any] struct {
+	Items []T `gorm:"-"`
+}
@@ -0,0 +1,37 @@
+package enums
+
+import (
+	"gorm.io/gorm"
+)
+
+type TicketStatus string
+
+const (
+	TicketOpen    TicketStatus = "open"
+	TicketPending TicketStatus = "pending"
+	TicketClosed  TicketStatus = "closed"
+)
+
+func (TicketStatus) EnumValues() []string {
+	return []string{string(TicketOpen), string(TicketPending), string(TicketClosed)}
+}
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+)
+
+func (Priority) EnumValues() []string {
+	return []string{"1", "2", "3"}
+}
+
+type Ticket struct {
+	gorm.Model
+	Title          string
+	Status         TicketStatus `gorm:"not null;default:open"`
+	PreviousStatus *TicketStatus
+	Priority       Priority
+}
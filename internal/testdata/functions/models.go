@@ -0,0 +1,80 @@
+package functions
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Pet struct {
+	gorm.Model
+	Name   string
+	UserID uint
+}
+
+type PetHistory struct {
+	UserID    uint `gorm:"primaryKey"`
+	PetID     uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+// Triggers executes the trigger function defined by LogPetHistory on PostgreSQL.
+func (Pet) Triggers(dialect string) []gormschema.Trigger {
+	if dialect != "postgres" {
+		return nil
+	}
+	return []gormschema.Trigger{
+		gormschema.NewTrigger(
+			gormschema.TriggerName("trg_insert_pet_history"),
+			gormschema.TriggerTime(gormschema.TriggerAfter),
+			gormschema.TriggerEvents(gormschema.TriggerInsert),
+			gormschema.ForEachRow(),
+			gormschema.TriggerFunction("log_pet_history"),
+		),
+	}
+}
+
+type LogPetHistory struct{}
+
+func (LogPetHistory) FunctionDef(dialect string) []gormschema.FunctionOption {
+	var stmt string
+	switch dialect {
+	case "postgres":
+		stmt = `CREATE OR REPLACE FUNCTION log_pet_history()
+RETURNS TRIGGER AS $$
+BEGIN
+	INSERT INTO pet_histories (user_id, pet_id, created_at)
+	VALUES (NEW.user_id, NEW.id, NEW.created_at);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql`
+	case "mysql":
+		stmt = `CREATE FUNCTION pet_count(uid BIGINT UNSIGNED) RETURNS BIGINT
+READS SQL DATA
+RETURN (SELECT COUNT(*) FROM pets WHERE user_id = uid)`
+	}
+	return []gormschema.FunctionOption{gormschema.CreateStmt(stmt)}
+}
+
+type ArchivePets struct{}
+
+func (ArchivePets) ProcedureDef(dialect string) []gormschema.FunctionOption {
+	var stmt string
+	switch dialect {
+	case "postgres":
+		stmt = `CREATE PROCEDURE archive_pets(before timestamptz)
+LANGUAGE SQL
+AS $$
+	UPDATE pets SET deleted_at = now() WHERE created_at < before;
+$$`
+	case "sqlserver":
+		stmt = `CREATE PROCEDURE archive_pets @before datetimeoffset
+AS
+BEGIN
+	UPDATE pets SET deleted_at = SYSDATETIMEOFFSET() WHERE created_at < @before;
+END`
+	}
+	return []gormschema.FunctionOption{gormschema.CreateStmt(stmt)}
+}
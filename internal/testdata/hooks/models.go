@@ -0,0 +1,36 @@
+package hooks
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type User struct {
+	gorm.Model
+	Name string
+}
+
+type Invoice struct {
+	gorm.Model
+	UserID uint
+	User   User
+	Amount int
+}
+
+// AtlasGormConfig returns the configuration used in production.
+func AtlasGormConfig(string) *gorm.Config {
+	return &gorm.Config{
+		NamingStrategy:                           schema.NamingStrategy{TablePrefix: "app_", SingularTable: true},
+		DisableForeignKeyConstraintWhenMigrating: true,
+	}
+}
+
+// AtlasGormOptions moves the invoices to the billing schema on PostgreSQL.
+func AtlasGormOptions(dialect string) []gormschema.Option {
+	if dialect != "postgres" {
+		return nil
+	}
+	return []gormschema.Option{gormschema.WithSchema("billing", Invoice{})}
+}
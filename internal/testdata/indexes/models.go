@@ -0,0 +1,83 @@
+package indexes
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Customer struct {
+	gorm.Model
+	Email   string `gorm:"size:255"`
+	Name    string `gorm:"size:255"`
+	Country string `gorm:"size:2"`
+}
+
+func (Customer) Indexes(dialect string) []gormschema.Index {
+	switch dialect {
+	case "postgres":
+		return []gormschema.Index{
+			gormschema.NewIndex("idx_customers_email",
+				gormschema.IndexExpression("lower(email)"),
+				gormschema.IndexPredicate("deleted_at IS NULL"),
+				gormschema.IndexUnique(),
+			),
+			gormschema.NewIndex("idx_customers_country",
+				gormschema.IndexColumns("country"),
+				gormschema.IndexInclude("name"),
+				gormschema.IndexMethod("BTREE"),
+			),
+		}
+	case "mysql":
+		return []gormschema.Index{
+			gormschema.NewIndex("idx_customers_email",
+				gormschema.IndexExpression("lower(email)"),
+				gormschema.IndexUnique(),
+			),
+			gormschema.NewIndex("idx_customers_country",
+				gormschema.IndexColumns("country", "name"),
+				gormschema.IndexMethod("BTREE"),
+			),
+		}
+	case "sqlite":
+		return []gormschema.Index{
+			gormschema.NewIndex("idx_customers_email",
+				gormschema.IndexExpression("lower(email)"),
+				gormschema.IndexPredicate("deleted_at IS NULL"),
+				gormschema.IndexUnique(),
+			),
+			gormschema.NewIndex("idx_customers_country", gormschema.IndexColumns("country", "name")),
+		}
+	case "sqlserver":
+		return []gormschema.Index{
+			gormschema.NewIndex("idx_customers_email",
+				gormschema.IndexColumns("email"),
+				gormschema.IndexPredicate("deleted_at IS NULL"),
+				gormschema.IndexUnique(),
+			),
+			gormschema.NewIndex("idx_customers_country",
+				gormschema.IndexColumns("country"),
+				gormschema.IndexInclude("name"),
+				gormschema.IndexMethod("NONCLUSTERED"),
+			),
+		}
+	case "spanner":
+		return []gormschema.Index{
+			gormschema.NewIndex("idx_customers_email", gormschema.IndexColumns("email"), gormschema.IndexUnique()),
+			gormschema.NewIndex("idx_customers_country", gormschema.IndexColumns("country"), gormschema.IndexInclude("name")),
+		}
+	}
+	return nil
+}
+
+// Order uses an expression index, which is not supported by SQL Server.
+type Order struct {
+	gorm.Model
+	Reference string
+}
+
+func (Order) Indexes(string) []gormschema.Index {
+	return []gormschema.Index{
+		gormschema.NewIndex("idx_orders_reference", gormschema.IndexExpression("upper(reference)")),
+	}
+}
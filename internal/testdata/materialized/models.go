@@ -0,0 +1,48 @@
+package materialized
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Order struct {
+	gorm.Model
+	CustomerID uint
+	Amount     int
+}
+
+type CustomerTotal struct {
+	CustomerID uint
+	Total      int
+}
+
+func (CustomerTotal) ViewDef(dialect string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{
+		gormschema.Materialized(),
+		gormschema.BuildStmt(func(db *gorm.DB) *gorm.DB {
+			return db.Model(&Order{}).Select("customer_id, SUM(amount) AS total").Group("customer_id")
+		}),
+	}
+}
+
+func (CustomerTotal) Indexes(dialect string) []gormschema.Index {
+	return []gormschema.Index{
+		gormschema.NewIndex("idx_customer_totals_customer_id", gormschema.IndexColumns("customer_id"), gormschema.IndexUnique()),
+	}
+}
+
+type LargeOrder struct {
+	ID     uint
+	Amount int
+}
+
+func (LargeOrder) ViewDef(dialect string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{
+		gormschema.Materialized(),
+		gormschema.WithNoData(),
+		gormschema.BuildStmt(func(db *gorm.DB) *gorm.DB {
+			return db.Model(&Order{}).Select("id, amount").Where("amount > ?", 1000)
+		}),
+	}
+}
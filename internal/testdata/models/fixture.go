@@ -0,0 +1,8 @@
+package models
+
+// PetFixture is used to seed pets in tests and is not a table.
+//
+//atlas:ignore
+type PetFixture struct {
+	Name string `gorm:"size:255"`
+}
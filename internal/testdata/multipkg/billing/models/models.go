@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/internal/testdata/multipkg/users/models"
+)
+
+type Invoice struct {
+	gorm.Model
+	Amount int
+	UserID uint
+	User   models.User
+}
@@ -0,0 +1,53 @@
+package multischema
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+// Account is assigned to the "crm" schema using the WithSchema option.
+type Account struct {
+	gorm.Model
+	Name string
+}
+
+// Invoice is assigned to the "billing" schema by its table name.
+type Invoice struct {
+	gorm.Model
+	Amount    int
+	AccountID uint
+	Account   Account
+	Tags      []Tag `gorm:"many2many:invoice_tags"`
+}
+
+func (Invoice) TableName() string {
+	return "billing.invoices"
+}
+
+type Tag struct {
+	ID   uint
+	Name string `gorm:"uniqueIndex"`
+}
+
+// AccountBalance is assigned to the "crm" schema using the WithSchema option.
+type AccountBalance struct {
+	AccountID uint
+	Balance   int
+}
+
+func (AccountBalance) ViewDef(dialect string) []gormschema.ViewOption {
+	return []gormschema.ViewOption{
+		gormschema.BuildStmt(func(db *gorm.DB) *gorm.DB {
+			return db.Model(&Invoice{}).Select("account_id, SUM(amount) AS balance").Group("account_id")
+		}),
+	}
+}
+
+// Payment is assigned to the "billing" schema using the WithSchema option.
+type Payment struct {
+	gorm.Model
+	Amount    int
+	AccountID uint
+	Account   Account
+}
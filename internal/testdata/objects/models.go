@@ -0,0 +1,35 @@
+package objects
+
+import (
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+// Objects are the schema objects the models depend on. The email domain
+// is listed first to show that objects are ordered by their dependencies.
+var Objects = []gormschema.Object{
+	gormschema.NewObject(gormschema.ObjectDomain, "email",
+		gormschema.ObjectStmt("postgres", "CREATE DOMAIN email AS citext CHECK (VALUE ~ '^[^@]+@[^@]+$')"),
+		gormschema.ObjectDependsOn("citext"),
+	),
+	gormschema.NewObject(gormschema.ObjectExtension, "citext",
+		gormschema.ObjectStmt("postgres", "CREATE EXTENSION IF NOT EXISTS citext"),
+	),
+	gormschema.NewObject(gormschema.ObjectSequence, "invoice_number_seq",
+		gormschema.ObjectStmt("postgres", "CREATE SEQUENCE invoice_number_seq START 1000"),
+		gormschema.ObjectStmt("sqlserver", "CREATE SEQUENCE invoice_number_seq START WITH 1000"),
+	),
+}
+
+type Customer struct {
+	gorm.Model
+	Email string `gorm:"type:email"`
+}
+
+type Invoice struct {
+	ID         uint
+	Number     int64 `gorm:"default:nextval('invoice_number_seq')"`
+	CustomerID uint
+	Customer   Customer
+}
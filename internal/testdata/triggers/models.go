@@ -0,0 +1,117 @@
+package triggers
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+type Pet struct {
+	gorm.Model
+	Name   string
+	UserID uint
+}
+
+type PetHistory struct {
+	UserID    uint `gorm:"primaryKey"`
+	PetID     uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+// Triggers defines the same triggers as the models package, using the typed options.
+func (Pet) Triggers(dialect string) []gormschema.Trigger {
+	var history, heart string
+	switch dialect {
+	case "mysql":
+		history = "INSERT INTO pet_histories (user_id, pet_id, created_at) VALUES (NEW.user_id, NEW.id, NOW(3))"
+		heart = "SET NEW.name = CONCAT(NEW.name, ' <3')"
+	case "sqlite":
+		history = "INSERT INTO pet_histories (user_id, pet_id, created_at) VALUES (NEW.user_id, NEW.id, datetime('now'))"
+		heart = "UPDATE pets SET name = name || ' <3' WHERE id = NEW.id"
+	case "postgres":
+		history = "INSERT INTO pet_histories (user_id, pet_id, created_at) VALUES (NEW.user_id, NEW.id, NEW.created_at)"
+		heart = "NEW.name := NEW.name || ' <3'"
+	case "sqlserver":
+		history = `INSERT INTO pet_histories (user_id, pet_id, created_at)
+		SELECT inserted.user_id, inserted.id, GETDATE()
+		FROM inserted
+		WHERE inserted.user_id IS NOT NULL`
+		heart = `INSERT INTO pets (name, user_id)
+		SELECT CONCAT(inserted.name, ' <3'), inserted.user_id
+		FROM inserted`
+	default:
+		return nil
+	}
+	// SQL Server does not support BEFORE triggers, and its triggers fire once per statement.
+	timing, row := gormschema.TriggerBefore, []gormschema.TriggerOption{gormschema.ForEachRow()}
+	if dialect == "sqlserver" {
+		timing, row = gormschema.TriggerInsteadOf, nil
+	}
+	return []gormschema.Trigger{
+		gormschema.NewTrigger(append([]gormschema.TriggerOption{
+			gormschema.TriggerName("trg_insert_pet_history"),
+			gormschema.TriggerTime(gormschema.TriggerAfter),
+			gormschema.TriggerEvents(gormschema.TriggerInsert),
+			gormschema.TriggerBody(history),
+		}, row...)...),
+		gormschema.NewTrigger(append([]gormschema.TriggerOption{
+			gormschema.TriggerName("trg_adding_heart_on_pet"),
+			gormschema.TriggerTime(timing),
+			gormschema.TriggerEvents(gormschema.TriggerInsert),
+			gormschema.TriggerBody(heart),
+		}, row...)...),
+	}
+}
+
+// Toy defines a trigger fired by multiple events, which is supported by PostgreSQL and SQL Server.
+type Toy struct {
+	gorm.Model
+	Name string
+}
+
+func (Toy) Triggers(string) []gormschema.Trigger {
+	return []gormschema.Trigger{
+		gormschema.NewTrigger(
+			gormschema.TriggerName("trg_toys_updated_at"),
+			gormschema.TriggerTime(gormschema.TriggerAfter),
+			gormschema.TriggerEvents(gormschema.TriggerInsert, gormschema.TriggerUpdate),
+			gormschema.TriggerBody("UPDATE toys SET updated_at = CURRENT_TIMESTAMP"),
+		),
+	}
+}
+
+// Category defines PostgreSQL triggers whose bodies return early, or end with a RETURN statement.
+type Category struct {
+	gorm.Model
+	Name     string
+	ParentID uint
+}
+
+func (Category) Triggers(string) []gormschema.Trigger {
+	return []gormschema.Trigger{
+		gormschema.NewTrigger(
+			gormschema.TriggerName("trg_categories_normalize"),
+			gormschema.TriggerTime(gormschema.TriggerBefore),
+			gormschema.TriggerEvents(gormschema.TriggerInsert),
+			gormschema.ForEachRow(),
+			gormschema.TriggerBody(`
+				IF NEW.parent_id = NEW.id THEN
+					RETURN NULL;
+				END IF;
+				NEW.name := trim(NEW.name)`),
+		),
+		gormschema.NewTrigger(
+			gormschema.TriggerName("trg_categories_protect_root"),
+			gormschema.TriggerTime(gormschema.TriggerBefore),
+			gormschema.TriggerEvents(gormschema.TriggerDelete),
+			gormschema.ForEachRow(),
+			gormschema.TriggerBody(`
+				IF OLD.parent_id = 0 THEN
+					RAISE EXCEPTION 'cannot delete a root category';
+				END IF;
+				RETURN OLD`),
+		),
+	}
+}
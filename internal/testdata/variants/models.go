@@ -0,0 +1,21 @@
+package variants
+
+import (
+	"gorm.io/gorm"
+)
+
+type Account struct {
+	gorm.Model
+	Number   int32 `gorm:"uniqueIndex"`
+	Priority int16
+	Balance  int64
+	Owner    string `gorm:"size:255"`
+}
+
+type Transfer struct {
+	ID        string `gorm:"primaryKey;size:36"`
+	AccountID uint
+	Account   Account
+	Amount    int32
+	Fee       uint32
+}
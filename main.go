@@ -2,11 +2,15 @@ package main
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"io"
+	"maps"
 	"os"
+	"path"
 	"reflect"
 	"slices"
 	"strings"
@@ -15,7 +19,9 @@ import (
 	"ariga.io/atlas-provider-gorm/gormschema"
 	"ariga.io/atlas/sdk/tmplrun"
 	"github.com/alecthomas/kong"
+	"github.com/pmezard/go-difflib/difflib"
 	"golang.org/x/tools/go/packages"
+	"gorm.io/gorm"
 )
 
 var (
@@ -26,7 +32,8 @@ var (
 
 func main() {
 	var cli struct {
-		Load LoadCmd `cmd:""`
+		Load    LoadCmd    `cmd:""`
+		Migrate MigrateCmd `cmd:""`
 	}
 	ctx := kong.Parse(&cli)
 	if err := ctx.Run(); err != nil {
@@ -37,41 +44,120 @@ func main() {
 
 // LoadCmd is a command to load models
 type LoadCmd struct {
-	Path      string   `help:"path to schema package" required:""`
-	BuildTags string   `help:"build tags to use" default:""`
-	Models    []string `help:"Models to load"`
-	Dialect   string   `help:"dialect to use" enum:"mysql,sqlite,postgres,sqlserver,spanner" required:""`
+	LoadFlags `embed:""`
+	Format    string `help:"output format" enum:"sql,hcl,json" default:"sql"`
+	HCLSchema string `name:"hcl-schema" help:"name of the schema in the HCL output. Required for mysql and mariadb"`
+	Check     string `help:"path to a schema snapshot to compare the output with. A diff is printed, and the command fails, if they differ"`
 	out       io.Writer
 }
 
-var viewDefiner = reflect.TypeOf((*gormschema.ViewDefiner)(nil)).Elem()
+// LoadFlags holds the flags that select the models and the dialect they are loaded for,
+// shared by the commands that load the models.
+type LoadFlags struct {
+	Path           []string `help:"paths to schema packages. Package patterns such as ./... are supported" required:""`
+	BuildTags      string   `help:"build tags to use" default:""`
+	Models         []string `help:"Models to load. Glob patterns such as Billing* are supported"`
+	Exclude        []string `help:"Models or package paths to exclude. Glob patterns and /... suffixes are supported"`
+	Dialect        string   `help:"dialect to use: mysql, mariadb, sqlite, postgres, cockroach, yugabyte, sqlserver, spanner, clickhouse, or a dialect registered by the models using gormschema.RegisterDialect" required:""`
+	DialectVersion string   `help:"version of the database server to generate the DDL for (e.g. 5.7)"`
+}
+
+var (
+	viewDefiner      = reflect.TypeOf((*gormschema.ViewDefiner)(nil)).Elem()
+	functionDefiner  = reflect.TypeOf((*gormschema.FunctionDefiner)(nil)).Elem()
+	procedureDefiner = reflect.TypeOf((*gormschema.ProcedureDefiner)(nil)).Elem()
+	gormModel        = reflect.TypeOf(gorm.Model{})
+	gormConfig       = reflect.TypeOf(gorm.Config{})
+	loaderOption     = reflect.TypeOf(gormschema.Option(nil))
+)
+
+// Names of the hooks a models package may define to configure the loader program.
+const (
+	// configHook returns the gorm.Config used for the given dialect.
+	configHook = "AtlasGormConfig"
+	// optionsHook returns the gormschema options used for the given dialect.
+	optionsHook = "AtlasGormOptions"
+)
 
 func (c *LoadCmd) Run() error {
+	if c.Check != "" && c.Format == "json" {
+		return errors.New("--check is not supported by the json format")
+	}
 	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule | packages.NeedDeps,
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule | packages.NeedDeps | packages.NeedSyntax,
 	}
 	if c.BuildTags != "" {
 		cfg.BuildFlags = []string{"-tags=" + c.BuildTags}
 	}
-	var models []model
-	switch pkgs, err := packages.Load(cfg, c.Path, viewDefiner.PkgPath()); {
-	case err != nil:
+	pkgs, err := packages.Load(cfg, append(slices.Clone(c.Path), viewDefiner.PkgPath())...)
+	if err != nil {
 		return fmt.Errorf("loading package: %w", err)
-	case len(pkgs) != 2:
-		return fmt.Errorf("missing package information for: %s", c.Path)
-	default:
-		schemaPkg, modelsPkg := pkgs[0], pkgs[1]
-		if schemaPkg.PkgPath != viewDefiner.PkgPath() {
-			schemaPkg, modelsPkg = pkgs[1], pkgs[0]
+	}
+	var (
+		schemaPkg  *packages.Package
+		modelsPkgs []*packages.Package
+	)
+	for _, pkg := range pkgs {
+		switch {
+		case pkg.PkgPath == viewDefiner.PkgPath():
+			schemaPkg = pkg
+		case len(pkg.Errors) > 0:
+			return fmt.Errorf("loading package %s: %v", pkg.ID, pkg.Errors[0])
+		// Commands cannot be imported by the loader program.
+		case pkg.Name != "main":
+			modelsPkgs = append(modelsPkgs, pkg)
+		}
+	}
+	if schemaPkg == nil || len(modelsPkgs) == 0 {
+		return fmt.Errorf("missing package information for: %s", strings.Join(c.Path, ", "))
+	}
+	slices.SortFunc(modelsPkgs, func(i, j *packages.Package) int {
+		return strings.Compare(i.PkgPath, j.PkgPath)
+	})
+	var definers []*types.Interface
+	for _, d := range []reflect.Type{viewDefiner, functionDefiner, procedureDefiner} {
+		definers = append(definers, schemaPkg.Types.Scope().Lookup(d.Name()).Type().Underlying().(*types.Interface))
+	}
+	var models []model
+	for _, pkg := range modelsPkgs {
+		models = append(models, gatherModels(pkg, definers...)...)
+	}
+	if models, err = filterModels(models, c.Models); err != nil {
+		return err
+	}
+	if models, err = excludeModels(models, c.Exclude); err != nil {
+		return err
+	}
+	var hooks []model
+	for _, pkg := range modelsPkgs {
+		h, err := gatherHooks(pkg)
+		if err != nil {
+			return err
+		}
+		hooks = append(hooks, h...)
+	}
+	if hooks, err = excludeModels(hooks, c.Exclude); err != nil {
+		return err
+	}
+	for _, name := range []string{configHook, optionsHook} {
+		if i := slices.IndexFunc(hooks, func(h model) bool { return h.Name == name }); i != -1 {
+			if j := slices.IndexFunc(hooks[i+1:], func(h model) bool { return h.Name == name }); j != -1 {
+				return fmt.Errorf("%s is defined by multiple packages: %s and %s", name, hooks[i].ImportPath, hooks[i+1+j].ImportPath)
+			}
 		}
-		models = gatherModels(modelsPkg, schemaPkg.Types.Scope().
-			Lookup(viewDefiner.Name()).Type().
-			Underlying().(*types.Interface))
 	}
+	// Hooks and models share the package aliases of the loader program.
+	all := slices.Concat(models, hooks)
+	aliasPackages(all)
+	models, hooks = all[:len(models)], all[len(models):]
 	s, err := tmplrun.New("gormschema", loaderTmpl, tmplrun.WithBuildTags(c.BuildTags)).
 		Run(Payload{
-			Models:  models,
-			Dialect: c.Dialect,
+			Models:         models,
+			Hooks:          hooks,
+			Dialect:        c.Dialect,
+			DialectVersion: c.DialectVersion,
+			Format:         c.Format,
+			HCLSchema:      c.HCLSchema,
 		})
 	if err != nil {
 		return err
@@ -79,23 +165,71 @@ func (c *LoadCmd) Run() error {
 	if c.out == nil {
 		c.out = os.Stdout
 	}
+	if c.Check != "" {
+		return checkSnapshot(c.out, c.Check, s)
+	}
 	_, err = fmt.Fprintln(c.out, s)
 	return err
 }
 
+// checkSnapshot compares the loaded schema with the snapshot at the given path, and writes a unified
+// diff to w if they differ. Position directives are ignored, as they depend on the location of the
+// source files.
+func checkSnapshot(w io.Writer, path, schema string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        snapshotLines(string(b)),
+		B:        snapshotLines(schema),
+		FromFile: path,
+		ToFile:   "models",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		return nil
+	}
+	if _, err := io.WriteString(w, diff); err != nil {
+		return err
+	}
+	return fmt.Errorf("schema does not match snapshot %s", path)
+}
+
+// snapshotLines splits the schema into lines, omitting the position directives.
+func snapshotLines(s string) []string {
+	return slices.DeleteFunc(difflib.SplitLines(strings.TrimRight(s, "\n")), func(l string) bool {
+		return strings.HasPrefix(l, "-- atlas:pos ")
+	})
+}
+
 type Payload struct {
-	Models  []model
-	Dialect string
+	Models []model
+	// Hooks are the functions of the models packages that configure the loader.
+	Hooks          []model
+	Dialect        string
+	DialectVersion string
+	Format         string
+	HCLSchema      string
 }
 
-func (p Payload) Imports() []string {
-	imports := make(map[string]struct{})
-	for _, m := range p.Models {
-		imports[m.ImportPath] = struct{}{}
+// importSpec describes a single import of the loader program.
+type importSpec struct {
+	Alias string
+	Path  string
+}
+
+func (p Payload) Imports() []importSpec {
+	imports := make(map[string]string)
+	for _, m := range slices.Concat(p.Models, p.Hooks) {
+		imports[m.ImportPath] = m.Alias
 	}
-	var result []string
-	for k := range imports {
-		result = append(result, k)
+	result := make([]importSpec, 0, len(imports))
+	for _, k := range slices.Sorted(maps.Keys(imports)) {
+		result = append(result, importSpec{Alias: imports[k], Path: k})
 	}
 	return result
 }
@@ -103,22 +237,106 @@ func (p Payload) Imports() []string {
 type model struct {
 	ImportPath string
 	PkgName    string
-	Name       string
-	Pos        string
+	// Alias is the name the package is imported as in case
+	// its name collides with another imported package.
+	Alias string
+	Name  string
+	Pos   string
 }
 
 func (m model) String() string {
+	if m.Alias != "" {
+		return fmt.Sprintf("%s.%s", m.Alias, m.Name)
+	}
 	return fmt.Sprintf("%s.%s", m.PkgName, m.Name)
 }
 
-func gatherModels(pkg *packages.Package, view *types.Interface) []model {
-	var models []model
+// aliasPackages sets an import alias for models whose package name
+// collides with another package imported by the loader program.
+func aliasPackages(models []model) {
+	var (
+		aliases = make(map[string]string)
+		// Names already used by the loader program, including its local variables.
+		used = map[string]bool{"fmt": true, "io": true, "os": true, "gormschema": true, "opts": true, "cfg": true, "stmts": true, "err": true}
+	)
+	paths := make(map[string]string)
+	for _, m := range models {
+		paths[m.ImportPath] = m.PkgName
+	}
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		name := paths[p]
+		if !used[name] {
+			used[name] = true
+			continue
+		}
+		alias := name
+		for i := 1; used[alias]; i++ {
+			alias = fmt.Sprintf("%s%d", name, i)
+		}
+		used[alias] = true
+		aliases[p] = alias
+	}
+	for i := range models {
+		models[i].Alias = aliases[models[i].ImportPath]
+	}
+}
+
+// Hook returns the hook with the given name, or nil if no models package defines it.
+func (p Payload) Hook(name string) *model {
+	for _, h := range p.Hooks {
+		if h.Name == name {
+			return &h
+		}
+	}
+	return nil
+}
+
+// gatherHooks returns the hooks defined by the package. That is, an AtlasGormConfig function that returns
+// the *gorm.Config of the given dialect, or an AtlasGormOptions function that returns its []gormschema.Option.
+func gatherHooks(pkg *packages.Package) ([]model, error) {
+	var hooks []model
+	for _, h := range []struct{ name, result, pkgPath string }{
+		{name: configHook, result: "*gorm.Config", pkgPath: gormConfig.PkgPath()},
+		{name: optionsHook, result: "[]gormschema.Option", pkgPath: loaderOption.PkgPath()},
+	} {
+		fn, ok := pkg.Types.Scope().Lookup(h.name).(*types.Func)
+		if !ok {
+			continue
+		}
+		// Types of other packages are qualified by their path, so they never match the expected result.
+		qf := func(p *types.Package) string {
+			if p.Path() == h.pkgPath {
+				return p.Name()
+			}
+			return p.Path()
+		}
+		sig := fn.Type().(*types.Signature)
+		if sig.Params().Len() != 1 || !types.Identical(sig.Params().At(0).Type(), types.Typ[types.String]) ||
+			sig.Results().Len() != 1 || types.TypeString(sig.Results().At(0).Type(), qf) != h.result {
+			return nil, fmt.Errorf("%s.%s must be of type func(dialect string) %s", pkg.PkgPath, h.name, h.result)
+		}
+		hooks = append(hooks, model{ImportPath: pkg.PkgPath, Name: h.name, PkgName: pkg.Name})
+	}
+	return hooks, nil
+}
+
+// ignoreDirective marks a type declaration that should be skipped by the loader.
+const ignoreDirective = "//atlas:ignore"
+
+// gatherModels returns the GORM models of the package, and the types
+// implementing one of the given interfaces (e.g. gormschema.ViewDefiner).
+func gatherModels(pkg *packages.Package, definers ...*types.Interface) []model {
+	var (
+		models  []model
+		ignored = ignoredTypes(pkg)
+	)
 	for k, v := range pkg.TypesInfo.Defs {
 		typ, ok := v.(*types.TypeName)
-		if !ok || !k.IsExported() {
+		// Skip unexported, function-scoped, ignored, alias and generic types.
+		if !ok || !k.IsExported() || typ.Parent() != pkg.Types.Scope() || ignored[k.Name] || typ.IsAlias() || isGeneric(typ.Type()) {
 			continue
 		}
-		if isGORMModel(k.Obj.Decl) || types.Implements(typ.Type(), view) {
+		if isGORMModel(typ.Type()) || slices.ContainsFunc(definers, func(d *types.Interface) bool { return types.Implements(typ.Type(), d) }) {
 			p := pkg.Fset.Position(k.Pos())
 			models = append(models, model{
 				ImportPath: pkg.PkgPath,
@@ -134,33 +352,159 @@ func gatherModels(pkg *packages.Package, view *types.Interface) []model {
 	return models
 }
 
-func isGORMModel(decl any) bool {
-	spec, ok := decl.(*ast.TypeSpec)
+// filterModels returns the models whose names, or package-qualified names (e.g. billing.Invoice),
+// match at least one of the given patterns. An error is returned if a pattern is malformed or
+// matches no model.
+func filterModels(models []model, patterns []string) ([]model, error) {
+	if len(patterns) == 0 {
+		return models, nil
+	}
+	matched := make([]bool, len(models))
+	for _, p := range patterns {
+		var found bool
+		for i, m := range models {
+			ok, err := path.Match(p, m.Name)
+			if err == nil && !ok {
+				ok, err = path.Match(p, m.PkgName+"."+m.Name)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid model pattern %q: %w", p, err)
+			}
+			if ok {
+				matched[i], found = true, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no models match %q", p)
+		}
+	}
+	var result []model
+	for i, m := range models {
+		if matched[i] {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// excludeModels returns the models that do not match any of the given patterns. A pattern
+// is matched against the model name, its package-qualified name and its package import path.
+// Patterns ending with "/..." exclude all packages under the given import path.
+func excludeModels(models []model, patterns []string) ([]model, error) {
+	if len(patterns) == 0 {
+		return models, nil
+	}
+	var result []model
+	for _, m := range models {
+		var excluded bool
+		for _, p := range patterns {
+			if prefix, ok := strings.CutSuffix(p, "/..."); ok && (m.ImportPath == prefix || strings.HasPrefix(m.ImportPath, prefix+"/")) {
+				excluded = true
+				break
+			}
+			for _, name := range []string{m.Name, m.PkgName + "." + m.Name, m.ImportPath} {
+				ok, err := path.Match(p, name)
+				if err != nil {
+					return nil, fmt.Errorf("invalid exclude pattern %q: %w", p, err)
+				}
+				excluded = excluded || ok
+			}
+		}
+		if !excluded {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// ignoredTypes returns the names of the types in the package that are
+// annotated with the ignore directive.
+func ignoredTypes(pkg *packages.Package) map[string]bool {
+	ignored := make(map[string]bool)
+	for _, f := range pkg.Syntax {
+		for _, d := range f.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range decl.Specs {
+				spec := s.(*ast.TypeSpec)
+				doc := spec.Doc
+				// The doc comment of a non-grouped declaration is attached to the GenDecl.
+				if doc == nil && !decl.Lparen.IsValid() {
+					doc = decl.Doc
+				}
+				if doc != nil && slices.ContainsFunc(doc.List, func(c *ast.Comment) bool {
+					return c.Text == ignoreDirective || strings.HasPrefix(c.Text, ignoreDirective+" ")
+				}) {
+					ignored[spec.Name.Name] = true
+				}
+			}
+		}
+	}
+	return ignored
+}
+
+// isGORMModel reports whether the given type is a GORM model. That is, a struct that embeds
+// gorm.Model (directly or through other embedded structs), has fields with gorm tags, or
+// implements the schema.Tabler interface.
+func isGORMModel(typ types.Type) bool {
+	st, ok := typ.Underlying().(*types.Struct)
 	if !ok {
 		return false
 	}
-	st, ok := spec.Type.(*ast.StructType)
-	if !ok {
+	return implementsTabler(typ) || isGORMStruct(st, make(map[*types.Struct]bool))
+}
+
+// isGeneric reports whether the type has type parameters
+// and therefore cannot be instantiated by the loader.
+func isGeneric(typ types.Type) bool {
+	n, ok := typ.(*types.Named)
+	return ok && n.TypeParams().Len() > 0
+}
+
+// isGORMStruct reports whether the struct, or one of its embedded structs,
+// embeds gorm.Model or contains fields with gorm tags.
+func isGORMStruct(st *types.Struct, seen map[*types.Struct]bool) bool {
+	if seen[st] {
 		return false
 	}
-	return slices.ContainsFunc(st.Fields.List, func(f *ast.Field) bool {
-		if len(f.Names) == 0 && embedsModel(f.Type) {
+	seen[st] = true
+	for i := range st.NumFields() {
+		if reflect.StructTag(st.Tag(i)).Get("gorm") != "" {
 			return true
 		}
-		// Look for gorm: tag.
-		return f.Tag != nil && reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("gorm") != ""
-	})
+		f := st.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		t := types.Unalias(f.Type())
+		if p, ok := t.(*types.Pointer); ok {
+			t = types.Unalias(p.Elem())
+		}
+		if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil &&
+			n.Obj().Pkg().Path() == gormModel.PkgPath() && n.Obj().Name() == gormModel.Name() {
+			return true
+		}
+		if est, ok := t.Underlying().(*types.Struct); ok && isGORMStruct(est, seen) {
+			return true
+		}
+	}
+	return false
 }
 
-// return gorm.Model from the selector expression
-func embedsModel(ex ast.Expr) bool {
-	s, ok := ex.(*ast.SelectorExpr)
+// implementsTabler reports whether the type (or a pointer to it)
+// has a "TableName() string" method, as defined by schema.Tabler.
+func implementsTabler(typ types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(typ), false, nil, "TableName")
+	fn, ok := obj.(*types.Func)
 	if !ok {
 		return false
 	}
-	id, ok := s.X.(*ast.Ident)
-	if !ok {
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
 		return false
 	}
-	return id.Name == "gorm" && s.Sel.Name == "Model"
+	b, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && b.Kind() == types.String
 }
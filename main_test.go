@@ -2,28 +2,44 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"ariga.io/atlas-provider-gorm/gormschema"
+	"ariga.io/atlas-provider-gorm/internal/testdata/functions"
+	"ariga.io/atlas-provider-gorm/internal/testdata/models"
+	"github.com/alecthomas/kong"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
 )
 
 func TestLoad(t *testing.T) {
-	for _, dialect := range []string{"mysql", "sqlite", "postgres", "sqlserver"} {
+	for _, dialect := range []string{"mysql", "sqlite", "postgres", "sqlserver", "spanner"} {
 		t.Run(dialect, func(t *testing.T) {
 			var buf bytes.Buffer
 			cmd := &LoadCmd{
-				Path:    "./internal/testdata/models",
-				Dialect: dialect,
-				out:     &buf,
+				LoadFlags: LoadFlags{
+					Path:    []string{"./internal/testdata/models"},
+					Dialect: dialect,
+				},
+				out: &buf,
 			}
 			err := cmd.Run()
 			require.NoError(t, err)
 			require.Contains(t, buf.String(), "CREATE TABLE")
 			require.Contains(t, buf.String(), "pets")
 			require.Contains(t, buf.String(), "users")
-			require.NotContains(t, buf.String(), "toys") // Struct without GORM annotations.
+			require.NotContains(t, buf.String(), "toys")         // Struct without GORM annotations.
+			require.NotContains(t, buf.String(), "pet_fixtures") // Struct annotated with atlas:ignore.
 		})
 	}
 }
@@ -31,7 +47,7 @@ func TestLoad(t *testing.T) {
 func TestDeterministicOutput(t *testing.T) {
 	expected, err := os.ReadFile("./gormschema/testdata/mysql_deterministic_output.sql")
 	require.NoError(t, err)
-	cmd := &LoadCmd{Path: "./internal/testdata/models", Dialect: "mysql"}
+	cmd := &LoadCmd{LoadFlags: LoadFlags{Path: []string{"./internal/testdata/models"}, Dialect: "mysql"}}
 	cwd, err := os.Getwd()
 	require.NoError(t, err)
 	for range 10 {
@@ -49,9 +65,11 @@ func TestCustomizeTablesLoad(t *testing.T) {
 	require.NoError(t, err)
 	var buf bytes.Buffer
 	cmd := &LoadCmd{
-		Path:    "./internal/testdata/customjointable",
-		Dialect: "mysql",
-		out:     &buf,
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/customjointable"},
+			Dialect: "mysql",
+		},
+		out: &buf,
 	}
 	require.NoError(t, cmd.Run())
 	expected, err := os.ReadFile("./gormschema/testdata/mysql_custom_join_table.sql")
@@ -63,10 +81,12 @@ func TestCustomizeTablesLoad(t *testing.T) {
 func TestBuildTags(t *testing.T) {
 	var buf bytes.Buffer
 	cmd := &LoadCmd{
-		Path:      "./internal/testdata/buildtags",
-		Dialect:   "mysql",
-		BuildTags: "buildtag",
-		out:       &buf,
+		LoadFlags: LoadFlags{
+			Path:      []string{"./internal/testdata/buildtags"},
+			Dialect:   "mysql",
+			BuildTags: "buildtag",
+		},
+		out: &buf,
 	}
 	err := cmd.Run()
 	require.NoError(t, err)
@@ -77,12 +97,382 @@ func TestBuildTags(t *testing.T) {
 func TestNonBuildTags(t *testing.T) {
 	var buf bytes.Buffer
 	cmd := &LoadCmd{
-		Path:    "./internal/testdata/buildtags",
-		Dialect: "mysql",
-		out:     &buf,
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/buildtags"},
+			Dialect: "mysql",
+		},
+		out: &buf,
 	}
 	err := cmd.Run()
 	require.NoError(t, err)
 	require.Contains(t, buf.String(), "CREATE TABLE `untagged_models`")
 	require.NotContains(t, buf.String(), "CREATE TABLE `tagged_models`")
 }
+
+func TestLoadModels(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/models"},
+			Dialect: "mysql",
+			Models:  []string{"User", "Hob*"},
+		},
+		out: &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), "CREATE TABLE `users`")
+	require.Contains(t, buf.String(), "CREATE TABLE `hobbies`")
+	require.NotContains(t, buf.String(), "CREATE TABLE `user_pet_histories`")
+	require.NotContains(t, buf.String(), "CREATE VIEW")
+	cmd.Models = []string{"User", "Unknown"}
+	require.EqualError(t, cmd.Run(), `no models match "Unknown"`)
+}
+
+func TestFilterModels(t *testing.T) {
+	models := []model{{Name: "BillingAccount"}, {Name: "BillingInvoice"}, {Name: "User"}}
+	filtered, err := filterModels(models, nil)
+	require.NoError(t, err)
+	require.Equal(t, models, filtered)
+	filtered, err = filterModels(models, []string{"User", "Billing*"})
+	require.NoError(t, err)
+	require.Equal(t, models, filtered)
+	filtered, err = filterModels(models, []string{"BillingI*"})
+	require.NoError(t, err)
+	require.Equal(t, []model{{Name: "BillingInvoice"}}, filtered)
+	_, err = filterModels(models, []string{"Billing["})
+	require.ErrorContains(t, err, `invalid model pattern "Billing["`)
+}
+
+func TestLoadMultiplePackages(t *testing.T) {
+	for _, path := range [][]string{
+		{"./internal/testdata/multipkg/..."},
+		{"./internal/testdata/multipkg/billing/models", "./internal/testdata/multipkg/users/models"},
+	} {
+		var buf bytes.Buffer
+		cmd := &LoadCmd{
+			LoadFlags: LoadFlags{
+				Path:    path,
+				Dialect: "mysql",
+			},
+			out: &buf,
+		}
+		require.NoError(t, cmd.Run())
+		require.Contains(t, buf.String(), "CREATE TABLE `users`")
+		require.Contains(t, buf.String(), "CREATE TABLE `invoices`")
+		require.Contains(t, buf.String(), "ALTER TABLE `invoices` ADD CONSTRAINT `fk_invoices_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)")
+	}
+}
+
+func TestAliasPackages(t *testing.T) {
+	models := []model{
+		{ImportPath: "example.com/users/models", PkgName: "models", Name: "User"},
+		{ImportPath: "example.com/billing/models", PkgName: "models", Name: "Invoice"},
+		{ImportPath: "example.com/billing/models", PkgName: "models", Name: "Payment"},
+		{ImportPath: "example.com/os", PkgName: "os", Name: "Process"},
+		{ImportPath: "example.com/opts", PkgName: "opts", Name: "Setting"},
+	}
+	aliasPackages(models)
+	require.Equal(t, []string{"models1.User", "models.Invoice", "models.Payment", "os1.Process", "opts1.Setting"}, []string{
+		models[0].String(), models[1].String(), models[2].String(), models[3].String(), models[4].String(),
+	})
+	require.Equal(t, []importSpec{
+		{Path: "example.com/billing/models"},
+		{Alias: "opts1", Path: "example.com/opts"},
+		{Alias: "os1", Path: "example.com/os"},
+		{Alias: "models1", Path: "example.com/users/models"},
+	}, Payload{Models: models}.Imports())
+}
+
+func TestLoadExclude(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/multipkg/..."},
+			Exclude: []string{"ariga.io/atlas-provider-gorm/internal/testdata/multipkg/billing/..."},
+			Dialect: "mysql",
+		},
+		out: &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), "CREATE TABLE `users`")
+	require.NotContains(t, buf.String(), "invoices")
+}
+
+func TestExcludeModels(t *testing.T) {
+	models := []model{
+		{ImportPath: "example.com/billing", PkgName: "billing", Name: "Invoice"},
+		{ImportPath: "example.com/billing/fixtures", PkgName: "fixtures", Name: "Invoice"},
+		{ImportPath: "example.com/users", PkgName: "users", Name: "User"},
+		{ImportPath: "example.com/users", PkgName: "users", Name: "UserFixture"},
+	}
+	filtered, err := excludeModels(models, nil)
+	require.NoError(t, err)
+	require.Equal(t, models, filtered)
+	filtered, err = excludeModels(models, []string{"*Fixture", "fixtures.Invoice"})
+	require.NoError(t, err)
+	require.Equal(t, []model{models[0], models[2]}, filtered)
+	filtered, err = excludeModels(models, []string{"example.com/billing/..."})
+	require.NoError(t, err)
+	require.Equal(t, models[2:], filtered)
+	filtered, err = excludeModels(models, []string{"example.com/billing"})
+	require.NoError(t, err)
+	require.Equal(t, models[1:], filtered)
+	_, err = excludeModels(models, []string{"User["})
+	require.ErrorContains(t, err, `invalid exclude pattern "User["`)
+}
+
+func TestLoadTypeInfo(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/embedded"},
+			Dialect: "mysql",
+		},
+		out: &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), "CREATE TABLE `authors`")      // Embeds gorm.Model through BaseModel.
+	require.Contains(t, buf.String(), "CREATE TABLE `books`")        // Embeds gorm.Model using an import alias.
+	require.Contains(t, buf.String(), "CREATE TABLE `book_reviews`") // Implements schema.Tabler.
+	require.NotContains(t, buf.String(), "base_models")
+	require.NotContains(t, buf.String(), "pages")
+}
+
+func TestLoadHCL(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/models"},
+			Exclude: []string{"Pet"}, // Triggers are not supported by the HCL output.
+			Dialect: "postgres",
+		},
+		Format: "hcl",
+		out:    &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), `table "users" {`)
+	require.Contains(t, buf.String(), `view "working_aged_users" {`)
+	require.NotContains(t, buf.String(), "CREATE TABLE")
+}
+
+func TestLoadJSON(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/models"},
+			Dialect: "sqlite",
+		},
+		Format: "json",
+		out:    &buf,
+	}
+	require.NoError(t, cmd.Run())
+	var info gormschema.SchemaInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &info))
+	require.Len(t, info.Tables, 5)
+	require.Equal(t, "hobbies", info.Tables[0].Name)
+	require.True(t, strings.HasSuffix(info.Tables[0].Pos, "/internal/testdata/models/user.go:17"))
+	require.Len(t, info.Views, 2)
+	require.Len(t, info.Triggers, 2)
+}
+
+func TestLoadDialectVersion(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:           []string{"./internal/testdata/models"},
+			Dialect:        "mysql",
+			DialectVersion: "5.5",
+		},
+		out: &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), "`created_at` datetime NULL")
+	buf.Reset()
+	cmd.Dialect, cmd.DialectVersion = "mariadb", ""
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), "`created_at` datetime(3) NULL")
+}
+
+func TestLoadFunctions(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/functions"},
+			Dialect: "postgres",
+		},
+		out: &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Regexp(t, `-- atlas:pos log_pet_history\[type=function\] .+/internal/testdata/functions/models.go:39`, buf.String())
+	require.Regexp(t, `-- atlas:pos archive_pets\[type=procedure\] .+/internal/testdata/functions/models.go:61`, buf.String())
+	require.Contains(t, buf.String(), "CREATE OR REPLACE FUNCTION log_pet_history()")
+}
+
+func TestMigrateDiff(t *testing.T) {
+	stmts, err := gormschema.New("sqlite").Load(models.Hobby{}, models.User{}, models.UserPetHistory{})
+	require.NoError(t, err)
+	var (
+		buf bytes.Buffer
+		ctx = context.Background()
+		dir = t.TempDir()
+		dev = "sqlite://dev?mode=memory&_fk=1"
+		cmd = &MigrateDiffCmd{Name: "init", Dir: dir, out: &buf}
+	)
+	require.NoError(t, cmd.diff(ctx, dev, []byte(stmts)))
+	files, err := filepath.Glob(filepath.Join(dir, "*_init.sql"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, files[0]+"\n", buf.String())
+	b, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(b), "CREATE TABLE `users`")
+	require.FileExists(t, filepath.Join(dir, "atlas.sum"))
+
+	// Running the command again reports that the directory is synced.
+	buf.Reset()
+	cmd.Name = ""
+	require.NoError(t, cmd.diff(ctx, dev, []byte(stmts)))
+	require.Equal(t, "The migration directory is synced with the models, no changes to be made\n", buf.String())
+	files, err = filepath.Glob(filepath.Join(dir, "*.sql"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// The migration directory must be in sync with its sum file.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20200101000000.sql"), []byte("SELECT 1;"), 0644))
+	require.ErrorContains(t, cmd.diff(ctx, dev, []byte(stmts)), "validating migration directory")
+
+	// Views, triggers and routines are not read back from the dev database.
+	cmd = &MigrateDiffCmd{Dir: t.TempDir(), LoadFlags: LoadFlags{Path: []string{"./internal/testdata/models"}, Dialect: "sqlite"}}
+	require.EqualError(t, cmd.Run(), "the loaded models define views and triggers, which are not supported by migrate diff")
+	stmts, err = gormschema.New("postgres").Load(functions.Pet{}, functions.LogPetHistory{}, functions.ArchivePets{})
+	require.NoError(t, err)
+	require.EqualError(t, cmd.diff(ctx, dev, []byte(stmts)), "the loaded models define functions, procedures and triggers, which are not supported by migrate diff")
+
+	// Other dialects require a dev database.
+	cmd = &MigrateDiffCmd{Dir: dir, LoadFlags: LoadFlags{Path: []string{"./internal/testdata/models"}, Dialect: "postgres"}}
+	require.EqualError(t, cmd.Run(), "--dev-url is required for the postgres dialect")
+}
+
+func TestLoadCheck(t *testing.T) {
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/models"},
+			Dialect: "mysql",
+		},
+		Format: "json",
+		Check:  "./gormschema/testdata/mysql_deterministic_output.sql",
+	}
+	require.EqualError(t, cmd.Run(), "--check is not supported by the json format")
+	// A matching snapshot prints nothing.
+	var buf bytes.Buffer
+	cmd.Format, cmd.out = "", &buf
+	require.NoError(t, cmd.Run())
+	require.Empty(t, buf.String())
+	// A drifted snapshot prints the diff and fails.
+	snapshot, err := os.ReadFile(cmd.Check)
+	require.NoError(t, err)
+	cmd.Check = filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(cmd.Check, append(snapshot, "\nCREATE TABLE `toys` (`id` bigint);"...), 0644))
+	require.EqualError(t, cmd.Run(), "schema does not match snapshot "+cmd.Check)
+	require.Contains(t, buf.String(), "--- "+cmd.Check+"\n+++ models\n")
+	require.Contains(t, buf.String(), "\n-CREATE TABLE `toys` (`id` bigint);\n")
+}
+
+func TestCheckSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(path, []byte("-- atlas:pos users[type=table] /home/a/user.go:9\n\nCREATE TABLE `users` (`id` bigint);\nCREATE TABLE `pets` (`id` bigint);\n"), 0644))
+	var buf bytes.Buffer
+	require.NoError(t, checkSnapshot(&buf, path, "-- atlas:pos users[type=table] /home/b/user.go:10\n\nCREATE TABLE `users` (`id` bigint);\nCREATE TABLE `pets` (`id` bigint);"))
+	require.Empty(t, buf.String())
+	err := checkSnapshot(&buf, path, "-- atlas:pos users[type=table] /home/b/user.go:10\n\nCREATE TABLE `users` (`id` bigint, `name` text);\nCREATE TABLE `pets` (`id` bigint);")
+	require.EqualError(t, err, "schema does not match snapshot "+path)
+	require.Equal(t, "--- "+path+"\n+++ models\n@@ -1,3 +1,3 @@\n \n"+
+		"-CREATE TABLE `users` (`id` bigint);\n"+
+		"+CREATE TABLE `users` (`id` bigint, `name` text);\n"+
+		" CREATE TABLE `pets` (`id` bigint);\n", buf.String())
+	require.ErrorContains(t, checkSnapshot(&buf, filepath.Join(t.TempDir(), "missing.sql"), ""), "reading snapshot")
+}
+
+func TestParseDialect(t *testing.T) {
+	var cli struct {
+		Load    LoadCmd    `cmd:""`
+		Migrate MigrateCmd `cmd:""`
+	}
+	parser, err := kong.New(&cli)
+	require.NoError(t, err)
+	// Dialects registered by the models package are validated by the loader program.
+	_, err = parser.Parse([]string{"load", "--path", "./models", "--dialect", "clickhouse"})
+	require.NoError(t, err)
+	require.Equal(t, "clickhouse", cli.Load.Dialect)
+	// The migrate diff command accepts the same flags to load the models.
+	_, err = parser.Parse([]string{"migrate", "diff", "--path", "./models", "--dialect", "mysql", "--dialect-version", "5.7", "--exclude", "Pet"})
+	require.NoError(t, err)
+	require.Equal(t, LoadFlags{Path: []string{"./models"}, Dialect: "mysql", DialectVersion: "5.7", Exclude: []string{"Pet"}}, cli.Migrate.Diff.LoadFlags)
+}
+
+func TestDialectImport(t *testing.T) {
+	// The CLI does not link the clickhouse dialect, which is imported by the loader program.
+	_, err := gormschema.New("clickhouse").Load(models.User{})
+	require.EqualError(t, err, `unsupported engine: clickhouse (missing import _ "ariga.io/atlas-provider-gorm/gormschema/clickhouse")`)
+}
+
+func TestGatherHooks(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedDeps | packages.NeedImports}, "./internal/testdata/hooks")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	hooks, err := gatherHooks(pkgs[0])
+	require.NoError(t, err)
+	require.Equal(t, []model{
+		{ImportPath: "ariga.io/atlas-provider-gorm/internal/testdata/hooks", PkgName: "hooks", Name: "AtlasGormConfig"},
+		{ImportPath: "ariga.io/atlas-provider-gorm/internal/testdata/hooks", PkgName: "hooks", Name: "AtlasGormOptions"},
+	}, hooks)
+	// A hook with an unexpected signature.
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "hooks.go", "package hooks\n\nfunc AtlasGormConfig() map[string]string { return nil }\n", 0)
+	require.NoError(t, err)
+	typesPkg, err := new(types.Config).Check("example.com/hooks", fset, []*ast.File{f}, nil)
+	require.NoError(t, err)
+	_, err = gatherHooks(&packages.Package{PkgPath: "example.com/hooks", Name: "hooks", Types: typesPkg})
+	require.EqualError(t, err, "example.com/hooks.AtlasGormConfig must be of type func(dialect string) *gorm.Config")
+}
+
+func TestLoadHooks(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := &LoadCmd{
+		LoadFlags: LoadFlags{
+			Path:    []string{"./internal/testdata/hooks"},
+			Dialect: "postgres",
+		},
+		out: &buf,
+	}
+	require.NoError(t, cmd.Run())
+	require.Contains(t, buf.String(), `CREATE TABLE "app_user"`)
+	require.Contains(t, buf.String(), `CREATE TABLE "billing"."app_invoice"`)
+	require.NotContains(t, buf.String(), "FOREIGN KEY")
+}
+
+func TestLoaderHooks(t *testing.T) {
+	var (
+		buf   bytes.Buffer
+		hooks = []model{
+			{ImportPath: "example.com/hooks", PkgName: "hooks", Name: configHook},
+			{ImportPath: "example.com/hooks", PkgName: "hooks", Name: optionsHook},
+		}
+	)
+	require.NoError(t, loaderTmpl.Execute(&buf, Payload{
+		Models:  []model{{ImportPath: "example.com/models", PkgName: "models", Name: "User", Pos: "models.go:10"}},
+		Hooks:   hooks,
+		Dialect: "mysql",
+	}))
+	src, err := format.Source(buf.Bytes())
+	require.NoError(t, err)
+	require.Contains(t, string(src), "\t\"example.com/hooks\"\n")
+	require.Contains(t, string(src), "if cfg := hooks.AtlasGormConfig(\"mysql\"); cfg != nil {\n\t\topts = append(opts, gormschema.WithConfig(cfg))\n\t}")
+	require.Contains(t, string(src), "opts = append(opts, hooks.AtlasGormOptions(\"mysql\")...)")
+	require.Contains(t, string(src), "gormschema.New(\"mysql\", opts...)")
+	// Both hooks are optional.
+	buf.Reset()
+	require.NoError(t, loaderTmpl.Execute(&buf, Payload{Hooks: hooks[1:], Dialect: "mysql"}))
+	require.NotContains(t, buf.String(), configHook)
+	require.Contains(t, buf.String(), optionsHook)
+}
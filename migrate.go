@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	_ "ariga.io/atlas/sql/mysql"
+	_ "ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	_ "ariga.io/atlas/sql/sqlite"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func init() {
+	// Atlas opens PostgreSQL dev databases using the "postgres" driver name,
+	// which is served by pgx, the driver used by GORM.
+	if !slices.Contains(sql.Drivers(), "postgres") {
+		sql.Register("postgres", stdlib.GetDefaultDriver())
+	}
+}
+
+// MigrateCmd groups the commands that manage a migration directory.
+type MigrateCmd struct {
+	Diff MigrateDiffCmd `cmd:"" help:"Write a new migration file with the changes between the migration directory and the models"`
+}
+
+// MigrateDiffCmd is a command to plan a new migration file from the models.
+type MigrateDiffCmd struct {
+	Name      string `arg:"" optional:"" help:"name of the migration file"`
+	Dir       string `help:"path to the migration directory" default:"./migrations"`
+	DevURL    string `help:"URL of the dev database used to replay the migration directory. Defaults to an in-memory SQLite database for the sqlite dialect"`
+	LoadFlags `embed:""`
+	out       io.Writer
+}
+
+func (c *MigrateDiffCmd) Run() error {
+	devURL := c.DevURL
+	switch {
+	case devURL != "":
+	case c.Dialect == "sqlite":
+		devURL = "sqlite://dev?mode=memory&_fk=1"
+	default:
+		return fmt.Errorf("--dev-url is required for the %s dialect", c.Dialect)
+	}
+	var desired bytes.Buffer
+	load := &LoadCmd{
+		LoadFlags: c.LoadFlags,
+		Format:    "sql",
+		out:       &desired,
+	}
+	if err := load.Run(); err != nil {
+		return err
+	}
+	return c.diff(context.Background(), devURL, desired.Bytes())
+}
+
+// uncapturedRe matches the statements creating objects that are not read back from
+// the dev database, and therefore cannot be planned by the diff.
+var uncapturedRe = regexp.MustCompile(`(?im)^\s*CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:MATERIALIZED\s+)?(VIEW|TRIGGER|FUNCTION|PROCEDURE)\b`)
+
+// diff plans the changes between the migration directory and the desired schema,
+// and writes them to a new migration file.
+func (c *MigrateDiffCmd) diff(ctx context.Context, devURL string, desired []byte) error {
+	if kinds := uncaptured(desired); len(kinds) > 0 {
+		if n := len(kinds); n > 1 {
+			kinds = append(kinds[:n-2], kinds[n-2]+" and "+kinds[n-1])
+		}
+		return fmt.Errorf("the loaded models define %s, which are not supported by migrate diff", strings.Join(kinds, ", "))
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("creating migration directory: %w", err)
+	}
+	dir, err := migrate.NewLocalDir(c.Dir)
+	if err != nil {
+		return err
+	}
+	if err := migrate.Validate(dir); err != nil {
+		return fmt.Errorf("validating migration directory: %w", err)
+	}
+	dev, err := sqlclient.Open(ctx, devURL)
+	if err != nil {
+		return fmt.Errorf("opening dev database: %w", err)
+	}
+	defer dev.Close()
+	// The desired state is computed by replaying the loaded schema on the dev database,
+	// the same way the current state is computed from the migration directory.
+	models := &migrate.MemDir{}
+	if err := models.WriteFile("schema.sql", desired); err != nil {
+		return err
+	}
+	sum, err := models.Checksum()
+	if err != nil {
+		return err
+	}
+	if err := migrate.WriteSumFile(models, sum); err != nil {
+		return err
+	}
+	ex, err := migrate.NewExecutor(dev.Driver, models, migrate.NopRevisionReadWriter{})
+	if err != nil {
+		return err
+	}
+	var (
+		state   migrate.StateReader = migrate.RealmConn(dev.Driver, nil)
+		planner                     = migrate.NewPlanner(dev.Driver, dir, migrate.PlanWithChecksum(true))
+		plan                        = planner.Plan
+	)
+	// Limit the diff to the schema the dev database is connected to, if any.
+	if dev.URL.Schema != "" {
+		state, plan = migrate.SchemaConn(dev.Driver, "", nil), planner.PlanSchema
+	}
+	p, err := plan(ctx, c.Name, migrate.StateReaderFunc(func(ctx context.Context) (*schema.Realm, error) {
+		return ex.Replay(ctx, state)
+	}))
+	if c.out == nil {
+		c.out = os.Stdout
+	}
+	switch {
+	case errors.Is(err, migrate.ErrNoPlan):
+		_, err = fmt.Fprintln(c.out, "The migration directory is synced with the models, no changes to be made")
+		return err
+	case err != nil:
+		return err
+	}
+	p.Version = migrate.NewVersion()
+	f, err := migrate.DefaultFormatter.FormatFile(p)
+	if err != nil {
+		return err
+	}
+	if err := planner.WritePlan(p); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.out, filepath.Join(c.Dir, f.Name()))
+	return err
+}
+
+// uncaptured returns the kinds of objects created by the given statements that are not supported by the diff.
+func uncaptured(stmts []byte) []string {
+	var kinds []string
+	for _, m := range uncapturedRe.FindAllSubmatch(stmts, -1) {
+		kind := strings.ToLower(string(m[1])) + "s"
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}